@@ -1,8 +1,10 @@
 package amqp
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"github.com/AeroAgency/golang-helpers-lib/requestid"
 	"github.com/rs/zerolog"
 	rabbitLib "github.com/streadway/amqp"
 	"sync"
@@ -325,6 +327,11 @@ func (client *Client) SetSilenceMode(mode bool) *Client {
 
 // Publish публикует сообщение в очередь
 func (client *Client) Publish(body string, routingKey string) error {
+	return client.PublishContext(context.Background(), body, routingKey)
+}
+
+// PublishContext публикует сообщение в очередь, передавая контекст трассировки в заголовках сообщения
+func (client *Client) PublishContext(ctx context.Context, body string, routingKey string) error {
 	if client == nil {
 		return errAvailable
 	}
@@ -342,6 +349,11 @@ func (client *Client) Publish(body string, routingKey string) error {
 		routingKey = client.config.Queue
 	}
 
+	logger := requestid.Logger(ctx, client.logger)
+	headers := rabbitLib.Table{}
+	span := startPublishSpan(ctx, routingKey, headers)
+	defer span.End()
+
 	err := client.channel.Publish(
 		client.config.Exchange, // exchange
 		routingKey,             // routing key
@@ -350,20 +362,29 @@ func (client *Client) Publish(body string, routingKey string) error {
 		rabbitLib.Publishing{
 			ContentType: contentType,
 			Body:        []byte(body),
+			Headers:     headers,
 		})
 	if err != nil {
-		client.logger.Error().Dict("error publish", zerolog.Dict().Str("addr", fmt.Sprintf("%s:%d", client.config.Host, client.config.Port)).Time("time", time.Now()).Err(err)).Msg("")
+		span.RecordError(err)
+		logger.Error().Dict("error publish", zerolog.Dict().Str("addr", fmt.Sprintf("%s:%d", client.config.Host, client.config.Port)).Time("time", time.Now()).Err(err)).Msg("")
 		return err
 	}
 
 	if !client.silenceMode {
-		client.logger.Info().Dict("publish in queue", zerolog.Dict().Str("addr", fmt.Sprintf("%s:%d", client.config.Host, client.config.Port)).Time("time", time.Now()).Str("queueName", client.config.Queue).Str("event_message", body)).Msg("")
+		logger.Info().Dict("publish in queue", zerolog.Dict().Str("addr", fmt.Sprintf("%s:%d", client.config.Host, client.config.Port)).Time("time", time.Now()).Str("queueName", client.config.Queue).Str("event_message", body)).Msg("")
 	}
 	return nil
 }
 
-// Publish публикует сообщение в очередь
+// PublishWithCount публикует сообщение в очередь с количеством попыток обработки
 func (client *Client) PublishWithCount(body string, routingKey string, countAttempt int) error {
+	return client.PublishWithCountContext(context.Background(), body, routingKey, countAttempt)
+}
+
+// PublishWithCountContext публикует сообщение в очередь с количеством попыток обработки,
+// передавая контекст трассировки и идентификаторы запроса в заголовках сообщения.
+// При повторной публикации полученного сообщения контекст берется из ContextFromDelivery
+func (client *Client) PublishWithCountContext(ctx context.Context, body string, routingKey string, countAttempt int) error {
 	if client == nil {
 		return errAvailable
 	}
@@ -381,6 +402,13 @@ func (client *Client) PublishWithCount(body string, routingKey string, countAtte
 		routingKey = client.config.Queue
 	}
 
+	logger := requestid.Logger(ctx, client.logger)
+	headers := rabbitLib.Table{
+		MessageHeaderCountAttempt: countAttempt,
+	}
+	span := startPublishSpan(ctx, routingKey, headers)
+	defer span.End()
+
 	err := client.channel.Publish(
 		client.config.Exchange, // exchange
 		routingKey,             // routing key
@@ -389,17 +417,16 @@ func (client *Client) PublishWithCount(body string, routingKey string, countAtte
 		rabbitLib.Publishing{
 			ContentType: contentType,
 			Body:        []byte(body),
-			Headers: rabbitLib.Table{
-				MessageHeaderCountAttempt: countAttempt,
-			},
+			Headers:     headers,
 		})
 	if err != nil {
-		client.logger.Error().Dict("error publish", zerolog.Dict().Str("addr", fmt.Sprintf("%s:%d", client.config.Host, client.config.Port)).Time("time", time.Now()).Err(err)).Msg("")
+		span.RecordError(err)
+		logger.Error().Dict("error publish", zerolog.Dict().Str("addr", fmt.Sprintf("%s:%d", client.config.Host, client.config.Port)).Time("time", time.Now()).Err(err)).Msg("")
 		return err
 	}
 
 	if !client.silenceMode {
-		client.logger.Info().Dict("publish in queue", zerolog.Dict().Str("addr", fmt.Sprintf("%s:%d", client.config.Host, client.config.Port)).Time("time", time.Now()).Str("queueName", client.config.Queue).Str("event_message", body)).Msg("")
+		logger.Info().Dict("publish in queue", zerolog.Dict().Str("addr", fmt.Sprintf("%s:%d", client.config.Host, client.config.Port)).Time("time", time.Now()).Str("queueName", client.config.Queue).Str("event_message", body)).Msg("")
 	}
 
 	return nil
@@ -415,6 +442,12 @@ func GetMessageCountAttempt(d *rabbitLib.Delivery) int {
 
 // PublishExchange публикует сообщение в exchange
 func (client *Client) PublishExchange(body string, exchange string) error {
+	return client.PublishExchangeContext(context.Background(), body, exchange)
+}
+
+// PublishExchangeContext публикует сообщение в exchange, передавая контекст трассировки
+// и идентификаторы запроса в заголовках сообщения
+func (client *Client) PublishExchangeContext(ctx context.Context, body string, exchange string) error {
 	if client == nil {
 		return errAvailable
 	}
@@ -423,6 +456,11 @@ func (client *Client) PublishExchange(body string, exchange string) error {
 		return errChannelIsNil
 	}
 
+	logger := requestid.Logger(ctx, client.logger)
+	headers := rabbitLib.Table{}
+	span := startPublishSpan(ctx, exchange, headers)
+	defer span.End()
+
 	err := client.channel.Publish(
 		exchange, // exchange
 		"",       // routing key
@@ -431,14 +469,16 @@ func (client *Client) PublishExchange(body string, exchange string) error {
 		rabbitLib.Publishing{
 			ContentType: "text/plain",
 			Body:        []byte(body),
+			Headers:     headers,
 		})
 	if err != nil {
-		client.logger.Error().Dict("error publish", zerolog.Dict().Str("addr", fmt.Sprintf("%s:%d", client.config.Host, client.config.Port)).Str("exchangeName", exchange).Err(err)).Msg("")
+		span.RecordError(err)
+		logger.Error().Dict("error publish", zerolog.Dict().Str("addr", fmt.Sprintf("%s:%d", client.config.Host, client.config.Port)).Str("exchangeName", exchange).Err(err)).Msg("")
 		return err
 	}
 
 	if !client.silenceMode {
-		client.logger.Info().Dict("publish in exchange", zerolog.Dict().Str("addr", fmt.Sprintf("%s:%d", client.config.Host, client.config.Port)).Str("exchangeName", exchange).Str("event_message", body)).Msg("")
+		logger.Info().Dict("publish in exchange", zerolog.Dict().Str("addr", fmt.Sprintf("%s:%d", client.config.Host, client.config.Port)).Str("exchangeName", exchange).Str("event_message", body)).Msg("")
 	}
 	return nil
 }
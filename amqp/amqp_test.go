@@ -1,8 +1,12 @@
 package amqp
 
 import (
+	"context"
+	"github.com/AeroAgency/golang-helpers-lib/requestid"
+	"github.com/AeroAgency/golang-helpers-lib/tracing"
 	"github.com/rs/zerolog"
 	rabbitLib "github.com/streadway/amqp"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"reflect"
 	"sync"
 	"testing"
@@ -171,6 +175,18 @@ func TestClient_PublishExchange(t *testing.T) {
 	client.PublishExchange("test", "exchange")
 }
 
+func TestClient_PublishContext(t *testing.T) {
+	client := NewClient(Config{}, zerolog.Logger{})
+	ctx := requestid.NewContext(context.Background(), requestid.Ids{RequestId: "req-1"})
+
+	if err := client.PublishWithCountContext(ctx, "test", "", 2); err == nil {
+		t.Fatalf("an error is expected for a client without a channel")
+	}
+	if err := client.PublishExchangeContext(ctx, "test", "exchange"); err == nil {
+		t.Fatalf("an error is expected for a client without a channel")
+	}
+}
+
 func TestConsumer_Init(t *testing.T) {
 	client := NewClient(Config{}, zerolog.Logger{})
 
@@ -192,3 +208,48 @@ func TestClient_ReConsume(t *testing.T) {
 	client.NewConsumer(&MockHandle{}, "")
 	client.reConsume()
 }
+
+func TestContextFromDelivery(t *testing.T) {
+	tp := sdktrace.NewTracerProvider()
+	ctx, span := tp.Tracer("test").Start(context.Background(), "handler")
+	defer span.End()
+
+	headers := rabbitLib.Table{}
+	tracing.Inject(ctx, headersCarrier(headers))
+	if _, ok := headers["traceparent"]; !ok {
+		t.Fatalf("traceparent header is expected in %v", headers)
+	}
+
+	extracted := ContextFromDelivery(context.Background(), &rabbitLib.Delivery{Headers: headers})
+	if tracing.TraceId(extracted) != span.SpanContext().TraceID().String() {
+		t.Fatalf("trace id '%s' is expected, got '%s'", span.SpanContext().TraceID(), tracing.TraceId(extracted))
+	}
+
+	if ctx := ContextFromDelivery(context.Background(), &rabbitLib.Delivery{}); tracing.TraceId(ctx) != "" {
+		t.Fatalf("empty trace id is expected for a delivery without headers")
+	}
+}
+
+func TestContextFromDelivery_RequestIds(t *testing.T) {
+	ctx := requestid.NewContext(context.Background(), requestid.Ids{RequestId: "req-1", CorrelationId: "chain-1"})
+	headers := rabbitLib.Table{}
+	span := startPublishSpan(ctx, "orders", headers)
+	span.End()
+
+	ids := requestid.FromContext(ContextFromDelivery(context.Background(), &rabbitLib.Delivery{Headers: headers}))
+	if ids.RequestId != "req-1" || ids.CorrelationId != "chain-1" {
+		t.Fatalf("request ids from the publishing context are expected, got %+v", ids)
+	}
+}
+
+func TestContextFromDelivery_InvalidRequestIds(t *testing.T) {
+	headers := rabbitLib.Table{
+		requestid.HeaderRequestId:     "req 1\r\n",
+		requestid.HeaderCorrelationId: "chain-1",
+	}
+
+	ids := requestid.FromContext(ContextFromDelivery(context.Background(), &rabbitLib.Delivery{Headers: headers}))
+	if ids.RequestId != "" || ids.CorrelationId != "chain-1" {
+		t.Fatalf("only the valid correlation id is expected, got %+v", ids)
+	}
+}
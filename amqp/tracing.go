@@ -0,0 +1,81 @@
+package amqp
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/AeroAgency/golang-helpers-lib/requestid"
+	"github.com/AeroAgency/golang-helpers-lib/tracing"
+	rabbitLib "github.com/streadway/amqp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// headersCarrier заголовки сообщения как носитель контекста трассировки
+type headersCarrier rabbitLib.Table
+
+func (c headersCarrier) Get(key string) string {
+	value, _ := c[key].(string)
+	return value
+}
+
+func (c headersCarrier) Set(key, value string) {
+	c[key] = value
+}
+
+func (c headersCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// ContextFromDelivery возвращает контекст, продолжающий трассировку из заголовков сообщения
+// и содержащий идентификаторы запроса, переданные при публикации
+func ContextFromDelivery(ctx context.Context, d *rabbitLib.Delivery) context.Context {
+	if d == nil || d.Headers == nil {
+		return ctx
+	}
+
+	carrier := headersCarrier(d.Headers)
+	ids := requestid.Ids{
+		RequestId:     carrier.Get(requestid.HeaderRequestId),
+		CorrelationId: carrier.Get(requestid.HeaderCorrelationId),
+	}
+	// Идентификаторы, не прошедшие проверку, отбрасываются
+	if !requestid.Valid(ids.RequestId) {
+		ids.RequestId = ""
+	}
+	if !requestid.Valid(ids.CorrelationId) {
+		ids.CorrelationId = ""
+	}
+	if ids.RequestId != "" || ids.CorrelationId != "" {
+		ctx = requestid.NewContext(ctx, ids)
+	}
+
+	return tracing.Extract(ctx, carrier)
+}
+
+// startPublishSpan создает спан публикации и записывает контекст трассировки
+// и идентификаторы запроса в заголовки
+func startPublishSpan(ctx context.Context, destination string, headers rabbitLib.Table) trace.Span {
+	ctx, span := otel.Tracer(tracing.InstrumentationName).Start(ctx, fmt.Sprintf("publish %s", destination),
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "rabbitmq"),
+			attribute.String("messaging.destination", destination),
+		),
+	)
+	tracing.Inject(ctx, headersCarrier(headers))
+	ids := requestid.FromContext(ctx)
+	if ids.RequestId != "" {
+		headers[requestid.HeaderRequestId] = ids.RequestId
+	}
+	if ids.CorrelationId != "" {
+		headers[requestid.HeaderCorrelationId] = ids.CorrelationId
+	}
+
+	return span
+}
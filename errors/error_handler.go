@@ -1,13 +1,13 @@
 package errors
 
 import (
-	"fmt"
-	tracerAdapter "github.com/AeroAgency/go-gin-tracer"
 	appLogger "github.com/AeroAgency/golang-helpers-lib/logger"
+	"github.com/AeroAgency/golang-helpers-lib/tracing"
 	"github.com/gin-gonic/gin"
 	"net/http"
 )
 
+// errorStatuses and errorMessages describe the builtin types, see RegisterType for the registry
 var (
 	errorStatuses = map[ErrorType]int{
 		NoType:             http.StatusInternalServerError,
@@ -32,48 +32,117 @@ var (
 	}
 )
 
+// DefaultUserIdKey is the gin context key of the user id logged with errors
+const DefaultUserIdKey = "userId"
+
 type ErrorHandler struct {
-	logger appLogger.AppLoggerInterface
+	logger     appLogger.AppLoggerInterface
+	translator *Translator
+	renderer   Renderer
+	production bool
+	userIdKey  string
+	logging    *logPolicies
 }
 
 func NewErrorHandler(logger appLogger.AppLoggerInterface) *ErrorHandler {
-	return &ErrorHandler{logger: logger}
+	return &ErrorHandler{logger: logger, renderer: LegacyRenderer{}, userIdKey: DefaultUserIdKey, logging: newLogPolicies()}
+}
+
+// SetUserIdKey sets the gin context key of the user id logged with errors
+func (h *ErrorHandler) SetUserIdKey(key string) *ErrorHandler {
+	h.userIdKey = key
+	return h
+}
+
+// SetRenderer sets the response format, e.g. ProblemRenderer for application/problem+json
+func (h *ErrorHandler) SetRenderer(renderer Renderer) *ErrorHandler {
+	h.renderer = renderer
+	return h
+}
+
+// SetProduction omits the error text from responses
+func (h *ErrorHandler) SetProduction(production bool) *ErrorHandler {
+	h.production = production
+	return h
+}
+
+// SetTranslator enables localized messages: the locale is negotiated from the Accept-Language header
+func (h *ErrorHandler) SetTranslator(translator *Translator) *ErrorHandler {
+	h.translator = translator
+	return h
 }
 
 type responseError struct {
-	Error   interface{} `json:"error"`
-	Message string      `json:"message"`
-	Debug   interface{} `json:"debug"`
+	Error      interface{}   `json:"error"`
+	Code       string        `json:"code"`
+	Message    string        `json:"message"`
+	Violations []Violation   `json:"violations,omitempty"`
+	Errors     []ProblemItem `json:"errors,omitempty"`
+	RequestId  string        `json:"requestId,omitempty"`
+	Debug      interface{}   `json:"debug"`
 }
 
 func (h ErrorHandler) HandleError(c *gin.Context, err error) {
-	tracer, _ := c.MustGet("tracer").(tracerAdapter.TracerInterface)
-	tracer.LogError(err)
-	var status int
-	errorType := GetType(err)
-	errStatus, _ := errorStatuses[errorType]
-	status = errStatus
-
-	responseData := getResponseError(err)
-	c.JSON(status, responseData)
-
-	if errorType == NoType {
-		stackTrace := GetStackTrace(Cause(err))
-		h.logger.Error(err, fmt.Sprintf("service error. stackTrace %v", stackTrace))
+	tracing.FromGin(c).LogError(err)
+	problem := NewProblem(err).WithRequest(c)
+	if locale, message, ok := h.translate(c, err); ok {
+		problem.Message = message
+		c.Header("Content-Language", locale)
 	}
+	if h.production {
+		problem.Debug = ""
+	}
+
+	renderer := h.renderer
+	if renderer == nil {
+		renderer = LegacyRenderer{}
+	}
+	renderer.Render(c, problem)
+
+	h.log(err, h.requestFields(c, problem)...)
 }
 
-func getResponseError(err error) responseError {
-	errorFormatted := responseError{}
-	GetType(err)
+// requestFields returns the request metadata logged with errors
+func (h ErrorHandler) requestFields(c *gin.Context, problem Problem) []interface{} {
+	var keysAndValues []interface{}
+	if c.Request != nil {
+		keysAndValues = append(keysAndValues, "method", c.Request.Method, "path", problem.Instance)
+	}
+	if problem.TraceId != "" {
+		keysAndValues = append(keysAndValues, "traceId", problem.TraceId)
+	}
+	if problem.RequestId != "" {
+		keysAndValues = append(keysAndValues, "requestId", problem.RequestId)
+	}
+	if userId, ok := c.Get(h.userIdKey); ok && h.userIdKey != "" {
+		keysAndValues = append(keysAndValues, "userId", userId)
+	}
 
-	status := errorStatuses[GetType(err)]
-	statusText := http.StatusText(status)
-	message := errorMessages[GetType(err)]
+	return keysAndValues
+}
 
-	errorFormatted.Error = statusText
-	errorFormatted.Message = message
-	errorFormatted.Debug = err.Error()
+// translate returns the message of Trans or of the error type in the negotiated locale
+func (h ErrorHandler) translate(c *gin.Context, err error) (string, string, bool) {
+	if h.translator == nil {
+		return "", "", false
+	}
 
-	return errorFormatted
+	var locales []string
+	if c.Request != nil {
+		locales = ParseAcceptLanguage(c.GetHeader("Accept-Language"))
+	}
+	if trans := GetTrans(err); trans != nil {
+		if message, locale, ok := h.translator.Translate(locales, trans.Msg, trans.Params...); ok {
+			return locale, message, true
+		}
+	}
+	if message, locale, ok := h.translator.Translate(locales, GetType(err).Info().Code); ok {
+		return locale, message, true
+	}
+
+	return "", "", false
+}
+
+func getResponseError(err error) responseError {
+	return legacyResponseError(NewProblem(err))
 }
@@ -116,6 +116,7 @@ func TestHandleError(t *testing.T) {
 type mockTracerAdapter struct {
 	mock.Mock
 	loggedErrors []interface{}
+	tags         map[string]interface{}
 }
 
 func (m *mockTracerAdapter) GetScope() *tracerAdapter.Scope {
@@ -129,8 +130,10 @@ func (m *mockTracerAdapter) Close() {
 }
 
 func (m *mockTracerAdapter) SetTag(key string, value interface{}) {
-	//TODO implement me
-	panic("implement me")
+	if m.tags == nil {
+		m.tags = map[string]interface{}{}
+	}
+	m.tags[key] = value
 }
 
 func (m *mockTracerAdapter) SetTags(list map[string]interface{}) {
@@ -163,7 +166,8 @@ func (m *mockTracerAdapter) LogError(err interface{}) {
 
 type mockAppLoggerInterface struct {
 	mock.Mock
-	loggedErrors []error
+	loggedErrors        []error
+	loggedKeysAndValues [][]interface{}
 }
 
 func (ml *mockAppLoggerInterface) Debug(msg string, keysAndValues ...interface{}) {
@@ -178,6 +182,7 @@ func (ml *mockAppLoggerInterface) Info(msg string, keysAndValues ...interface{})
 
 func (ml *mockAppLoggerInterface) Error(err error, message string, keysAndValues ...interface{}) {
 	ml.loggedErrors = append(ml.loggedErrors, err)
+	ml.loggedKeysAndValues = append(ml.loggedKeysAndValues, keysAndValues)
 }
 
 func TestHandleError_NoType(t *testing.T) {
@@ -268,3 +273,14 @@ func TestHandleError_NoType_WrappedErrorWithStackTrace(t *testing.T) {
 func TestNewErrorHandler(t *testing.T) {
 	NewErrorHandler(&mockAppLoggerInterface{})
 }
+
+func TestHandleError_WithoutTracer(t *testing.T) {
+	mockLogger := &mockAppLoggerInterface{}
+	c := &gin.Context{
+		Writer: ResponseWriterMock{},
+	}
+
+	h := ErrorHandler{logger: mockLogger}
+	assert.NotPanics(t, func() { h.HandleError(c, errors.New("unknown error")) })
+	assert.Len(t, mockLogger.loggedErrors, 1)
+}
@@ -13,7 +13,7 @@ const (
 	ServiceUnavailable
 	Forbidden
 	Unauthorized
-	//add any type you want
+	// service specific types are added with RegisterType
 
 	Internal = NoType
 )
@@ -23,6 +23,9 @@ type ErrorType uint
 type AppError struct {
 	errorType     ErrorType
 	originalError error
+	cause         error
+	violations    *[]Violation // a pointer keeps AppError comparable for errors.Is
+	fields        *Fields
 	Trans         *Trans
 }
 
@@ -35,6 +38,12 @@ type stackTracer interface {
 	StackTrace() errors.StackTrace
 }
 
+// Error returns the application code of the type, so that the type itself
+// can be used as a target of errors.Is: errors.Is(err, NotFound)
+func (t ErrorType) Error() string {
+	return t.Info().Code
+}
+
 // Error returns the message of a AppError
 func (e AppError) Error() string {
 	return e.originalError.Error()
@@ -55,28 +64,54 @@ func (t ErrorType) Wrap(err error, msg string) AppError {
 	return t.Wrapf(err, msg)
 }
 
-// Wrapf creates a new wrapped error with formatted message
+// Wrapf creates a new wrapped error with formatted message.
+// If the wrap chain of err contains an AppError, its type and translation are kept
 func (t ErrorType) Wrapf(err error, msg string, args ...interface{}) AppError {
 	wrappedError := errors.Wrapf(err, msg, args...)
-	if customErr, ok := err.(AppError); ok {
+	if customErr, ok := appErrorOf(err); ok {
 		return AppError{
 			errorType:     customErr.errorType,
 			originalError: wrappedError,
+			cause:         err,
+			violations:    customErr.violations,
 			Trans:         customErr.Trans,
 		}
 	}
 
-	return AppError{errorType: t, originalError: wrappedError}
+	return AppError{errorType: t, originalError: wrappedError, cause: err}
 }
 
+// Unwrap returns the error passed to Wrap, nil for errors created with New
 func (e AppError) Unwrap() error {
-	return errors.Unwrap(e.originalError)
+	return e.cause
+}
+
+// Is reports whether the error has the target type: errors.Is(err, NotFound)
+func (e AppError) Is(target error) bool {
+	if errorType, ok := target.(ErrorType); ok {
+		return e.errorType == errorType
+	}
+
+	return false
+}
+
+// As sets the error type into the *ErrorType target
+func (e AppError) As(target interface{}) bool {
+	if errorType, ok := target.(*ErrorType); ok {
+		*errorType = e.errorType
+		return true
+	}
+
+	return false
 }
 
 func (e AppError) T(msg string, params ...string) AppError {
 	return AppError{
 		errorType:     e.errorType,
 		originalError: e.originalError,
+		cause:         e.cause,
+		violations:    e.violations,
+		fields:        e.fields,
 		Trans: &Trans{
 			Msg:    msg,
 			Params: params,
@@ -89,24 +124,53 @@ func Cause(err error) error {
 	return errors.Cause(err)
 }
 
-// GetType returns the error type
+// GetType returns the type of the first AppError in the wrap chain.
+// Types missing in the registry are reported as NoType
 func GetType(err error) ErrorType {
-	if customErr, ok := err.(AppError); ok {
+	if customErr, ok := appErrorOf(err); ok && customErr.errorType.IsRegistered() {
 		return customErr.errorType
 	}
 
 	return NoType
 }
 
+// GetTrans returns the translation of the first AppError in the wrap chain
+func GetTrans(err error) *Trans {
+	if customErr, ok := appErrorOf(err); ok {
+		return customErr.Trans
+	}
+
+	return nil
+}
+
+// appErrorOf returns the first AppError in the wrap chain.
+// A MultiError is described by an AppError of its effective type
+func appErrorOf(err error) (AppError, bool) {
+	for err != nil {
+		switch e := err.(type) {
+		case AppError:
+			return e, true
+		case *MultiError:
+			return e.appError(), true
+		}
+		err = errors.Unwrap(err)
+	}
+
+	return AppError{}, false
+}
+
 func Is(err error, target error) bool {
 	return errors.Is(err, target)
 }
 
+// GetStackTrace returns the stack trace of the first AppError in the wrap chain,
+// or of the first error that has one
 func GetStackTrace(err error) errors.StackTrace {
-	if customErr, ok := err.(AppError); ok {
+	if customErr, ok := appErrorOf(err); ok {
 		err = customErr.originalError
 	}
-	if stacked, ok := err.(stackTracer); ok {
+	var stacked stackTracer
+	if errors.As(err, &stacked) {
 		return stacked.StackTrace()
 	}
 	return errors.StackTrace{}
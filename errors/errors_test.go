@@ -2,9 +2,11 @@ package errors
 
 import (
 	"fmt"
+	"net/http"
 	"testing"
 
 	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
 )
 
 func TestErrorType(t *testing.T) {
@@ -137,3 +139,86 @@ func TestWrapf(t *testing.T) {
 		t.Errorf("Expected wrapped error message: double wrapped error: wrapped error with formatting: original error, but got: %s", doubleWrappedErr.originalError.Error())
 	}
 }
+
+func TestRegisterType(t *testing.T) {
+	paymentRequired := RegisterType(TypeInfo{
+		HttpStatus: http.StatusPaymentRequired,
+		GrpcCode:   codes.FailedPrecondition,
+		Message:    "Требуется оплата.",
+		Code:       "PAYMENT_REQUIRED",
+	})
+
+	err := paymentRequired.New("payment required")
+	if GetType(err) != paymentRequired {
+		t.Errorf("Expected error type to be registered type, but got: %v", GetType(err))
+	}
+
+	respErr := getResponseError(err)
+	if respErr.Code != "PAYMENT_REQUIRED" || respErr.Error != http.StatusText(http.StatusPaymentRequired) {
+		t.Errorf("Expected payment required response, but got: %+v", respErr)
+	}
+
+	partial := RegisterType(TypeInfo{HttpStatus: http.StatusTooManyRequests})
+	if partial.Info().Code != NoType.Info().Code || partial.Info().GrpcCode != codes.Internal {
+		t.Errorf("Expected missing fields to be taken from NoType, but got: %+v", partial.Info())
+	}
+
+	if typ := GetType(ErrorType(1000).New("unknown type")); typ != NoType {
+		t.Errorf("Expected unregistered type to be reported as NoType, but got: %v", typ)
+	}
+}
+
+func TestSetTypeInfo(t *testing.T) {
+	info := NotFound.Info()
+	defer SetTypeInfo(NotFound, info)
+
+	SetTypeInfo(NotFound, TypeInfo{HttpStatus: http.StatusGone, Message: "Запись удалена."})
+	if NotFound.Info().HttpStatus != http.StatusGone || NotFound.Info().Message != "Запись удалена." {
+		t.Errorf("Expected NotFound info to be replaced, but got: %+v", NotFound.Info())
+	}
+}
+
+func TestWrapChain(t *testing.T) {
+	appErr := NotFound.New("order not found").T("order.not_found", "42")
+	wrapped := errors.Wrap(fmt.Errorf("repository: %w", appErr), "service")
+
+	if typ := GetType(wrapped); typ != NotFound {
+		t.Errorf("Expected error type NotFound through the wrap chain, but got: %v", typ)
+	}
+	if trans := GetTrans(wrapped); trans == nil || trans.Msg != "order.not_found" {
+		t.Errorf("Expected translation through the wrap chain, but got: %v", trans)
+	}
+	if len(GetStackTrace(wrapped)) == 0 {
+		t.Error("Expected non-empty stack trace through the wrap chain")
+	}
+	if rewrapped := BadRequest.Wrap(wrapped, "handler"); rewrapped.errorType != NotFound {
+		t.Errorf("Expected Wrap to keep the type of the wrapped AppError, but got: %v", rewrapped.errorType)
+	}
+}
+
+func TestIsErrorType(t *testing.T) {
+	wrapped := fmt.Errorf("service: %w", NotFound.New("order not found"))
+
+	if !Is(wrapped, NotFound) {
+		t.Error("Expected Is to match the error type through the wrap chain")
+	}
+	if Is(wrapped, Conflict) {
+		t.Error("Expected Is not to match another error type")
+	}
+
+	var typ ErrorType
+	if !errors.As(wrapped, &typ) || typ != NotFound {
+		t.Errorf("Expected As to extract NotFound, but got: %v", typ)
+	}
+}
+
+func TestUnwrap(t *testing.T) {
+	originalErr := errors.New("original error")
+
+	if cause := BadRequest.Wrap(originalErr, "wrapped").Unwrap(); cause != originalErr {
+		t.Errorf("Expected Unwrap to return the wrapped error, but got: %v", cause)
+	}
+	if cause := BadRequest.New("bad request").Unwrap(); cause != nil {
+		t.Errorf("Expected Unwrap of a new error to be nil, but got: %v", cause)
+	}
+}
@@ -0,0 +1,76 @@
+package errors
+
+import (
+	"runtime"
+
+	"github.com/pkg/errors"
+)
+
+// Fields is the key/value context of an error, e.g. identifiers of the processed entities
+type Fields map[string]interface{}
+
+// Frame is a stack trace frame suitable for structured logging
+type Frame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// WithField returns a copy of the error with the context field added
+func (e AppError) WithField(key string, value interface{}) AppError {
+	return e.WithFields(Fields{key: value})
+}
+
+// WithFields returns a copy of the error with the context fields added
+func (e AppError) WithFields(fields Fields) AppError {
+	merged := make(Fields, len(fields))
+	if e.fields != nil {
+		for key, value := range *e.fields {
+			merged[key] = value
+		}
+	}
+	for key, value := range fields {
+		merged[key] = value
+	}
+	e.fields = &merged
+
+	return e
+}
+
+// GetFields returns the context fields of all AppErrors in the wrap chain,
+// the fields of outer errors take precedence
+func GetFields(err error) Fields {
+	var fields Fields
+	for err != nil {
+		if customErr, ok := err.(AppError); ok && customErr.fields != nil {
+			if fields == nil {
+				fields = Fields{}
+			}
+			for key, value := range *customErr.fields {
+				if _, ok := fields[key]; !ok {
+					fields[key] = value
+				}
+			}
+		}
+		err = errors.Unwrap(err)
+	}
+
+	return fields
+}
+
+// GetStackFrames returns the stack trace of GetStackTrace as a list of frames
+func GetStackFrames(err error) []Frame {
+	stackTrace := GetStackTrace(err)
+	frames := make([]Frame, 0, len(stackTrace))
+	for _, frame := range stackTrace {
+		pc := uintptr(frame) - 1
+		fn := runtime.FuncForPC(pc)
+		if fn == nil {
+			continue
+		}
+		file, line := fn.FileLine(pc)
+		frames = append(frames, Frame{Function: fn.Name(), File: file, Line: line})
+	}
+
+	return frames
+}
@@ -0,0 +1,56 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithField(t *testing.T) {
+	inner := NoType.New("db timeout").WithField("orderId", 42).WithField("attempt", 1)
+	outer := NoType.Wrap(fmt.Errorf("repository: %w", inner), "service").WithFields(Fields{"attempt": 3, "userId": "u1"})
+
+	assert.Equal(t, Fields{"orderId": 42, "attempt": 3, "userId": "u1"}, GetFields(outer))
+	assert.Equal(t, Fields{"orderId": 42, "attempt": 1}, GetFields(inner))
+	assert.Nil(t, GetFields(NoType.New("no fields")))
+	assert.Equal(t, Fields{"orderId": 42}, GetFields(BadRequest.New("bad").WithField("orderId", 42).T("order.invalid")))
+}
+
+func TestGetStackFrames(t *testing.T) {
+	frames := GetStackFrames(NoType.New("db timeout"))
+
+	var found bool
+	for _, frame := range frames {
+		if strings.HasSuffix(frame.Function, "TestGetStackFrames") {
+			found = true
+			assert.True(t, strings.HasSuffix(frame.File, "fields_test.go"))
+			assert.NotZero(t, frame.Line)
+		}
+	}
+	assert.True(t, found, "Expected the test function in the stack frames: %+v", frames)
+}
+
+func TestHandleError_StructuredLog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockLogger := &mockAppLoggerInterface{}
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/orders/42", nil)
+	c.Header(traceIdHeader, "trace-1")
+	c.Set("tracer", &mockTracerAdapter{})
+	c.Set(DefaultUserIdKey, "u1")
+
+	NewErrorHandler(mockLogger).HandleError(c, NoType.New("db timeout").WithField("orderId", 42))
+
+	assert.Len(t, mockLogger.loggedKeysAndValues, 1)
+	keysAndValues := mockLogger.loggedKeysAndValues[0]
+	assert.Equal(t, []interface{}{
+		"method", http.MethodPost, "path", "/orders/42", "traceId", "trace-1", "userId", "u1", "orderId", 42, "stackTrace",
+	}, keysAndValues[:len(keysAndValues)-1])
+	assert.NotEmpty(t, keysAndValues[len(keysAndValues)-1].([]Frame))
+}
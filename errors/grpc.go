@@ -0,0 +1,140 @@
+package errors
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// metadata keys of errdetails.ErrorInfo used to pass Trans
+const (
+	grpcTransKey        = "trans"
+	grpcTransParamsKey  = "trans.params"
+	grpcTransParamsSize = "trans.params.count"
+)
+
+// GRPCStatus allows grpc to convert a returned AppError into its status
+func (e AppError) GRPCStatus() *status.Status {
+	return ToGrpcStatus(e)
+}
+
+// ToGrpcStatus converts the error into a gRPC status. The code is taken from the error type,
+// the application code and translation are added as errdetails.ErrorInfo.
+// The message of internal errors is replaced with the default one to hide the details
+func ToGrpcStatus(err error) *status.Status {
+	if err == nil {
+		return status.New(codes.OK, "")
+	}
+
+	errorType := GetType(err)
+	info := errorType.Info()
+	message := err.Error()
+	if errorType == NoType {
+		message = info.Message
+	}
+
+	errorInfo := &errdetails.ErrorInfo{Reason: info.Code}
+	if trans := GetTrans(err); trans != nil {
+		errorInfo.Metadata = map[string]string{
+			grpcTransKey:        trans.Msg,
+			grpcTransParamsSize: strconv.Itoa(len(trans.Params)),
+		}
+		for i, param := range trans.Params {
+			errorInfo.Metadata[grpcTransParamsKey+"."+strconv.Itoa(i)] = param
+		}
+	}
+
+	st := status.New(info.GrpcCode, message)
+	if withDetails, detailsErr := st.WithDetails(errorInfo); detailsErr == nil {
+		return withDetails
+	}
+
+	return st
+}
+
+// FromGrpcStatus converts a gRPC status into an AppError. The type is found by the application code
+// from errdetails.ErrorInfo, otherwise by the status code
+func FromGrpcStatus(st *status.Status) AppError {
+	errorType := typeByGrpcCode(st.Code())
+	appErr := AppError{originalError: errors.New(st.Message())}
+
+	for _, detail := range st.Details() {
+		errorInfo, ok := detail.(*errdetails.ErrorInfo)
+		if !ok {
+			continue
+		}
+		if registered, ok := typeByCode(errorInfo.GetReason()); ok {
+			errorType = registered
+		}
+		appErr.Trans = transFromMetadata(errorInfo.GetMetadata())
+	}
+	appErr.errorType = errorType
+
+	return appErr
+}
+
+// FromGrpcError converts an error returned by a gRPC client into an AppError
+func FromGrpcError(err error) AppError {
+	if st, ok := status.FromError(err); ok {
+		return FromGrpcStatus(st)
+	}
+
+	return NoType.Wrap(err, "grpc error")
+}
+
+// UnaryServerInterceptor converts errors returned by unary handlers into gRPC statuses
+// and logs them according to the log policy of their type
+func (h ErrorHandler) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, h.grpcError(err, info.FullMethod)
+		}
+
+		return resp, nil
+	}
+}
+
+// StreamServerInterceptor converts errors returned by stream handlers into gRPC statuses
+// and logs them according to the log policy of their type
+func (h ErrorHandler) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := handler(srv, ss); err != nil {
+			return h.grpcError(err, info.FullMethod)
+		}
+
+		return nil
+	}
+}
+
+func (h ErrorHandler) grpcError(err error, method string) error {
+	if _, ok := appErrorOf(err); !ok {
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+	}
+
+	h.log(err, "method", method)
+
+	return ToGrpcStatus(err).Err()
+}
+
+func transFromMetadata(metadata map[string]string) *Trans {
+	msg, ok := metadata[grpcTransKey]
+	if !ok {
+		return nil
+	}
+
+	trans := &Trans{Msg: msg}
+	count, _ := strconv.Atoi(metadata[grpcTransParamsSize])
+	for i := 0; i < count; i++ {
+		trans.Params = append(trans.Params, metadata[grpcTransParamsKey+"."+strconv.Itoa(i)])
+	}
+
+	return trans
+}
@@ -0,0 +1,73 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestToGrpcStatus(t *testing.T) {
+	err := NotFound.New("order not found").T("order.not_found", "42")
+
+	st := ToGrpcStatus(err)
+	assert.Equal(t, codes.NotFound, st.Code())
+	assert.Equal(t, "order not found", st.Message())
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	internal := ToGrpcStatus(errors.New("db connection refused"))
+	assert.Equal(t, codes.Internal, internal.Code())
+	assert.Equal(t, errorMessages[NoType], internal.Message())
+}
+
+func TestFromGrpcStatus(t *testing.T) {
+	appErr := FromGrpcError(ToGrpcStatus(Conflict.New("duplicate").T("order.duplicate", "a", "b")).Err())
+
+	assert.Equal(t, Conflict, GetType(appErr))
+	assert.Equal(t, "duplicate", appErr.Error())
+	assert.Equal(t, &Trans{Msg: "order.duplicate", Params: []string{"a", "b"}}, appErr.Trans)
+
+	appErr = FromGrpcStatus(status.New(codes.PermissionDenied, "denied"))
+	assert.Equal(t, Forbidden, GetType(appErr))
+	assert.Nil(t, appErr.Trans)
+
+	assert.Equal(t, NoType, GetType(FromGrpcError(errors.New("plain error"))))
+}
+
+func TestFromGrpcStatus_AmbiguousCode(t *testing.T) {
+	RegisterType(TypeInfo{HttpStatus: http.StatusTooManyRequests})
+	RegisterType(TypeInfo{HttpStatus: http.StatusPaymentRequired})
+
+	appErr := FromGrpcStatus(ToGrpcStatus(Internal.New("boom")))
+	assert.Equal(t, NoType, GetType(appErr))
+	assert.Equal(t, http.StatusInternalServerError, GetType(appErr).Info().HttpStatus)
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	mockLogger := &mockAppLoggerInterface{}
+	interceptor := NewErrorHandler(mockLogger).UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/orders.Orders/Get"}
+
+	_, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, BadRequest.New("bad request")
+	})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Len(t, mockLogger.loggedErrors, 0)
+
+	_, err = interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, errors.New("unknown error")
+	})
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Len(t, mockLogger.loggedErrors, 1)
+
+	downstream := status.Error(codes.Unavailable, "downstream")
+	_, err = interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		return nil, downstream
+	})
+	assert.Equal(t, downstream, err)
+}
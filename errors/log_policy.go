@@ -0,0 +1,172 @@
+package errors
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	appLogger "github.com/AeroAgency/golang-helpers-lib/logger"
+	"github.com/AeroAgency/golang-helpers-lib/metrics"
+)
+
+// LogLevel is the level the ErrorHandler logs errors of a type with
+type LogLevel int
+
+const (
+	LogSilent LogLevel = iota // not logged
+	LogDebug
+	LogInfo
+	LogWarn  // logged via Warn if the logger implements logger.WarnLoggerInterface, via Info otherwise
+	LogError // logged with the stack trace
+)
+
+// LogPolicy describes how the ErrorHandler logs errors of a type
+type LogPolicy struct {
+	Level LogLevel
+	// Every logs only each n-th error of the type, 0 and 1 log every error
+	Every int
+	// Limit is the max number of logged errors of the type per Interval, 0 means no limit.
+	// With a zero Interval the limit applies to the whole lifetime of the ErrorHandler
+	Limit    int
+	Interval time.Duration
+}
+
+// DefaultLogPolicy is used for types without a policy: internal errors are logged
+// with the stack trace, the others are not logged
+func DefaultLogPolicy(errorType ErrorType) LogPolicy {
+	if errorType == NoType {
+		return LogPolicy{Level: LogError}
+	}
+
+	return LogPolicy{Level: LogSilent}
+}
+
+type logCounter struct {
+	seen        int
+	windowStart time.Time
+	windowCount int
+}
+
+// logPolicies holds the policies and the sampling state shared by the copies of an ErrorHandler
+type logPolicies struct {
+	mu       sync.Mutex
+	policies map[ErrorType]LogPolicy
+	counters map[ErrorType]*logCounter
+	metrics  metrics.Metrics
+	now      func() time.Time
+}
+
+func newLogPolicies() *logPolicies {
+	return &logPolicies{
+		policies: map[ErrorType]LogPolicy{},
+		counters: map[ErrorType]*logCounter{},
+		now:      time.Now,
+	}
+}
+
+// allow returns the level for the error of the type, LogSilent if the error is sampled out
+func (p *logPolicies) allow(errorType ErrorType) LogLevel {
+	if p == nil {
+		return DefaultLogPolicy(errorType).Level
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	policy, ok := p.policies[errorType]
+	if !ok {
+		policy = DefaultLogPolicy(errorType)
+	}
+	if policy.Level == LogSilent {
+		return LogSilent
+	}
+
+	counter, ok := p.counters[errorType]
+	if !ok {
+		counter = &logCounter{}
+		p.counters[errorType] = counter
+	}
+
+	counter.seen++
+	if policy.Every > 1 && (counter.seen-1)%policy.Every != 0 {
+		return LogSilent
+	}
+
+	if policy.Limit > 0 {
+		now := p.now()
+		if policy.Interval > 0 && now.Sub(counter.windowStart) >= policy.Interval {
+			counter.windowStart = now
+			counter.windowCount = 0
+		}
+		if counter.windowCount >= policy.Limit {
+			return LogSilent
+		}
+		counter.windowCount++
+	}
+
+	return policy.Level
+}
+
+// SetLogPolicy sets how errors of the type are logged, see DefaultLogPolicy for the types without a policy
+func (h *ErrorHandler) SetLogPolicy(errorType ErrorType, policy LogPolicy) *ErrorHandler {
+	h.logPolicies().mu.Lock()
+	defer h.logPolicies().mu.Unlock()
+
+	h.logging.policies[errorType] = policy
+	delete(h.logging.counters, errorType)
+	return h
+}
+
+// SetMetrics enables counting handled errors by type in metrics.AppError
+func (h *ErrorHandler) SetMetrics(m metrics.Metrics) *ErrorHandler {
+	h.logPolicies().metrics = m
+	return h
+}
+
+func (h *ErrorHandler) logPolicies() *logPolicies {
+	if h.logging == nil {
+		h.logging = newLogPolicies()
+	}
+
+	return h.logging
+}
+
+// log counts the error in metrics and logs it according to the policy of its type
+// with the context fields as structured fields
+func (h ErrorHandler) log(err error, keysAndValues ...interface{}) {
+	errorType := GetType(err)
+	if h.logging != nil && h.logging.metrics != nil {
+		_ = h.logging.metrics.Inc(metrics.AppError.Name, errorType.Info().Code)
+	}
+
+	level := h.logging.allow(errorType)
+	if level == LogSilent {
+		return
+	}
+
+	fields := GetFields(err)
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		keysAndValues = append(keysAndValues, key, fields[key])
+	}
+
+	switch level {
+	case LogDebug:
+		h.logger.Debug("service error", append(keysAndValues, "error", err.Error())...)
+	case LogInfo:
+		h.logger.Info("service error", append(keysAndValues, "error", err.Error())...)
+	case LogWarn:
+		if warnLogger, ok := h.logger.(appLogger.WarnLoggerInterface); ok {
+			warnLogger.Warn("service error", append(keysAndValues, "error", err.Error())...)
+		} else {
+			h.logger.Info("service error", append(keysAndValues, "error", err.Error())...)
+		}
+	default:
+		keysAndValues = append(keysAndValues, "stackTrace", GetStackFrames(Cause(err)))
+		h.logger.Error(err, "service error", keysAndValues...)
+	}
+}
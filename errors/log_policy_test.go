@@ -0,0 +1,146 @@
+package errors
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type mockMetrics struct {
+	incs map[string][]string
+}
+
+func (m *mockMetrics) Inc(metricName string, labelValues ...string) error {
+	if m.incs == nil {
+		m.incs = map[string][]string{}
+	}
+	m.incs[metricName] = append(m.incs[metricName], labelValues...)
+	return nil
+}
+
+func (m *mockMetrics) Observe(metricName string, value float64, labelValues ...string) error {
+	return nil
+}
+
+type levelLogger struct {
+	debug, info, errors int
+	lastKeysAndValues   []interface{}
+}
+
+func (l *levelLogger) Debug(msg string, keysAndValues ...interface{}) {
+	l.debug++
+	l.lastKeysAndValues = keysAndValues
+}
+
+func (l *levelLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.info++
+	l.lastKeysAndValues = keysAndValues
+}
+
+func (l *levelLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	l.errors++
+	l.lastKeysAndValues = keysAndValues
+}
+
+func TestLogPolicy_Default(t *testing.T) {
+	logger := &levelLogger{}
+	h := NewErrorHandler(logger)
+
+	handle(h, NotFound.New("order not found"))
+	handle(h, ServiceUnavailable.New("payments are down"))
+	handle(h, NoType.New("db timeout"))
+
+	assert.Equal(t, levelLogger{errors: 1, lastKeysAndValues: logger.lastKeysAndValues}, *logger)
+}
+
+func TestLogPolicy_Levels(t *testing.T) {
+	logger := &levelLogger{}
+	h := NewErrorHandler(logger).
+		SetLogPolicy(NotFound, LogPolicy{Level: LogDebug}).
+		SetLogPolicy(BadRequest, LogPolicy{Level: LogInfo}).
+		SetLogPolicy(ServiceUnavailable, LogPolicy{Level: LogWarn}).
+		SetLogPolicy(NoType, LogPolicy{Level: LogSilent})
+
+	handle(h, NotFound.New("order not found").WithField("orderId", 42))
+	assert.Equal(t, 1, logger.debug)
+	assert.Equal(t, []interface{}{"method", "GET", "path", "/orders/42", "traceId", "trace-1", "orderId", 42, "error", "order not found"}, logger.lastKeysAndValues)
+
+	handle(h, BadRequest.New("bad request"))
+	assert.Equal(t, 1, logger.info)
+
+	handle(h, ServiceUnavailable.New("payments are down"))
+	assert.Equal(t, 2, logger.info)
+	assert.NotContains(t, logger.lastKeysAndValues, "level")
+
+	handle(h, NoType.New("db timeout"))
+	assert.Equal(t, 0, logger.errors)
+}
+
+type warnLogger struct {
+	levelLogger
+	warn int
+}
+
+func (l *warnLogger) Warn(msg string, keysAndValues ...interface{}) {
+	l.warn++
+	l.lastKeysAndValues = keysAndValues
+}
+
+func TestLogPolicy_Warn(t *testing.T) {
+	logger := &warnLogger{}
+	h := NewErrorHandler(logger).SetLogPolicy(ServiceUnavailable, LogPolicy{Level: LogWarn})
+
+	handle(h, ServiceUnavailable.New("payments are down"))
+	assert.Equal(t, 1, logger.warn)
+	assert.Equal(t, 0, logger.info)
+	assert.Contains(t, logger.lastKeysAndValues, "payments are down")
+}
+
+func TestLogPolicy_Sampling(t *testing.T) {
+	logger := &levelLogger{}
+	h := NewErrorHandler(logger).SetLogPolicy(Conflict, LogPolicy{Level: LogInfo, Every: 3})
+	for i := 0; i < 7; i++ {
+		handle(h, Conflict.New("order already paid"))
+	}
+	assert.Equal(t, 3, logger.info)
+}
+
+func TestLogPolicy_RateLimit(t *testing.T) {
+	logger := &levelLogger{}
+	h := NewErrorHandler(logger).SetLogPolicy(RequestTimeout, LogPolicy{Level: LogInfo, Limit: 2, Interval: time.Minute})
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	h.logging.now = func() time.Time { return now }
+
+	for i := 0; i < 5; i++ {
+		handle(h, RequestTimeout.New("upstream timeout"))
+	}
+	assert.Equal(t, 2, logger.info)
+
+	now = now.Add(time.Minute)
+	handle(h, RequestTimeout.New("upstream timeout"))
+	assert.Equal(t, 3, logger.info)
+}
+
+func TestLogPolicy_LimitWithoutInterval(t *testing.T) {
+	logger := &levelLogger{}
+	now := time.Now()
+	h := NewErrorHandler(logger).SetLogPolicy(RequestTimeout, LogPolicy{Level: LogInfo, Limit: 2})
+	h.logging.now = func() time.Time { return now }
+
+	for i := 0; i < 5; i++ {
+		now = now.Add(time.Hour)
+		handle(h, RequestTimeout.New("timeout"))
+	}
+	assert.Equal(t, 2, logger.info)
+}
+
+func TestLogPolicy_Metrics(t *testing.T) {
+	m := &mockMetrics{}
+	h := NewErrorHandler(&levelLogger{}).SetMetrics(m)
+
+	handle(h, NotFound.New("order not found"))
+	handle(h, NoType.New("db timeout"))
+
+	assert.Equal(t, map[string][]string{"app_error_total": {"NOT_FOUND", "APP_ERROR"}}, m.incs)
+}
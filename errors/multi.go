@@ -0,0 +1,135 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// MultiErrorType chooses the effective type of a MultiError by its children types:
+// NoType if any child is an internal error, otherwise the type with the highest HTTP status,
+// the first one on ties
+var MultiErrorType = func(types []ErrorType) ErrorType {
+	if len(types) == 0 {
+		return NoType
+	}
+
+	effective := types[0]
+	for _, errorType := range types {
+		if errorType == NoType {
+			return NoType
+		}
+		if errorType.Info().HttpStatus > effective.Info().HttpStatus {
+			effective = errorType
+		}
+	}
+
+	return effective
+}
+
+// MultiError collects several errors, e.g. of a batch operation
+type MultiError struct {
+	errs []error
+}
+
+// NewMultiError creates a MultiError from the non-nil errors
+func NewMultiError(errs ...error) *MultiError {
+	return (&MultiError{}).Append(errs...)
+}
+
+// Append adds the non-nil errors, the children of a MultiError are added one by one
+func (m *MultiError) Append(errs ...error) *MultiError {
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if multi, ok := err.(*MultiError); ok {
+			m.errs = append(m.errs, multi.errs...)
+			continue
+		}
+		m.errs = append(m.errs, err)
+	}
+
+	return m
+}
+
+// Errors returns the collected errors
+func (m *MultiError) Errors() []error {
+	return m.errs
+}
+
+// ErrorOrNil returns nil if no errors were collected
+func (m *MultiError) ErrorOrNil() error {
+	if m == nil || len(m.errs) == 0 {
+		return nil
+	}
+
+	return m
+}
+
+// Type returns the effective type chosen by MultiErrorType
+func (m *MultiError) Type() ErrorType {
+	types := make([]ErrorType, 0, len(m.errs))
+	for _, err := range m.errs {
+		types = append(types, GetType(err))
+	}
+
+	return MultiErrorType(types)
+}
+
+// Error returns the messages of all collected errors
+func (m *MultiError) Error() string {
+	if len(m.errs) == 1 {
+		return m.errs[0].Error()
+	}
+
+	messages := make([]string, 0, len(m.errs))
+	for _, err := range m.errs {
+		messages = append(messages, err.Error())
+	}
+
+	return fmt.Sprintf("%d errors occurred: %s", len(m.errs), strings.Join(messages, "; "))
+}
+
+// Unwrap returns the collected errors
+func (m *MultiError) Unwrap() []error {
+	return m.errs
+}
+
+// Is reports whether any of the collected errors matches the target
+func (m *MultiError) Is(target error) bool {
+	for _, err := range m.errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// As finds the first collected error that matches the target
+func (m *MultiError) As(target interface{}) bool {
+	for _, err := range m.errs {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// appError returns the AppError describing the MultiError: its effective type
+// and the violations of all children
+func (m *MultiError) appError() AppError {
+	appErr := AppError{errorType: m.Type(), originalError: m}
+	var violations []Violation
+	for _, err := range m.errs {
+		violations = append(violations, GetViolations(err)...)
+	}
+	if len(violations) > 0 {
+		appErr.violations = &violations
+	}
+
+	return appErr
+}
@@ -0,0 +1,74 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiError(t *testing.T) {
+	notFound := NotFound.New("order not found")
+	conflict := Conflict.New("order already paid")
+	multi := NewMultiError(nil, notFound).Append(NewMultiError(conflict), nil)
+
+	assert.Equal(t, []error{notFound, conflict}, multi.Errors())
+	assert.Equal(t, "2 errors occurred: order not found; order already paid", multi.Error())
+	assert.Equal(t, Conflict, GetType(fmt.Errorf("import: %w", multi)))
+	assert.True(t, errors.Is(multi, NotFound))
+	assert.True(t, errors.Is(multi, conflict))
+	assert.False(t, errors.Is(multi, Forbidden))
+
+	var appErr AppError
+	assert.True(t, errors.As(multi, &appErr))
+	assert.Equal(t, notFound, appErr)
+
+	internal := NewMultiError(notFound, errors.New("connection refused"))
+	assert.Equal(t, NoType, GetType(internal))
+
+	assert.Nil(t, NewMultiError().ErrorOrNil())
+	assert.Nil(t, NewMultiError(nil).ErrorOrNil())
+	assert.Equal(t, "order not found", NewMultiError(notFound).Error())
+}
+
+func TestMultiError_Wrap(t *testing.T) {
+	multi := NewMultiError(
+		NewValidationError("invalid row", Violation{Field: "rows.0.email", Rule: "email"}),
+		NewValidationError("invalid row", Violation{Field: "rows.3.name", Rule: "required"}),
+	)
+
+	wrapped := NoType.Wrap(multi, "bulk import")
+	assert.Equal(t, BadRequest, GetType(wrapped))
+	assert.Equal(t, []Violation{
+		{Field: "rows.0.email", Rule: "email"},
+		{Field: "rows.3.name", Rule: "required"},
+	}, GetViolations(wrapped))
+}
+
+func TestMultiError_Response(t *testing.T) {
+	err := NewMultiError(
+		NotFound.New("order not found"),
+		NewValidationError("invalid email", Violation{Field: "email", Rule: "email"}),
+	)
+
+	w := handle(NewErrorHandler(&mockAppLoggerInterface{}), err)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var body responseError
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, []ProblemItem{
+		{Status: http.StatusNotFound, Code: NotFound.Info().Code, Message: NotFound.Info().Message},
+		{Status: http.StatusBadRequest, Code: BadRequest.Info().Code, Message: BadRequest.Info().Message,
+			Violations: []Violation{{Field: "email", Rule: "email"}}},
+	}, body.Errors)
+
+	w = handle(NewErrorHandler(&mockAppLoggerInterface{}).SetRenderer(ProblemRenderer{}), err)
+	var problem struct {
+		Errors []ProblemItem `json:"errors"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Len(t, problem.Errors, 2)
+}
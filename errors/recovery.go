@@ -0,0 +1,48 @@
+package errors
+
+import (
+	"net/http"
+
+	"github.com/AeroAgency/golang-helpers-lib/tracing"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// RecoveryMiddleware converts a panic in the following handlers into an internal AppError
+// with the stack trace of the panic, marks the tracer span as failed and responds via HandleError.
+// If the response has already been written, the error is only logged. http.ErrAbortHandler
+// is re-panicked so that net/http aborts the connection
+func (h *ErrorHandler) RecoveryMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			if recovered == http.ErrAbortHandler {
+				panic(recovered)
+			}
+
+			tracer := tracing.FromGin(c)
+			tracer.SetTag("error", true)
+
+			err := panicError(recovered)
+			c.Abort()
+			if c.Writer.Written() {
+				tracer.LogError(err)
+				h.log(err, h.requestFields(c, NewProblem(err).WithRequest(c))...)
+				return
+			}
+			h.HandleError(c, err)
+		}()
+
+		c.Next()
+	}
+}
+
+// panicError creates an internal AppError from a recovered value,
+// the stack trace is captured inside the deferred call and starts at the panic
+func panicError(recovered interface{}) AppError {
+	err, _ := recovered.(error)
+	return AppError{errorType: NoType, originalError: errors.Errorf("panic: %v", recovered), cause: err}
+}
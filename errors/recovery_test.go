@@ -0,0 +1,87 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecoveryMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockTracer := &mockTracerAdapter{}
+	mockLogger := &mockAppLoggerInterface{}
+	h := NewErrorHandler(mockLogger)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("tracer", mockTracer)
+	}, h.RecoveryMiddleware())
+	router.GET("/orders/:id", func(c *gin.Context) {
+		var orders map[string]string
+		orders[c.Param("id")] = "paid"
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/orders/42", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	var body responseError
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, NoType.Info().Code, body.Code)
+	assert.Contains(t, body.Debug, "panic: assignment to entry in nil map")
+
+	assert.Equal(t, true, mockTracer.tags["error"])
+	assert.Len(t, mockTracer.loggedErrors, 1)
+	assert.Len(t, mockLogger.loggedErrors, 1)
+	assert.NotEmpty(t, GetStackTrace(mockLogger.loggedErrors[0]))
+	assert.Contains(t, Cause(mockLogger.loggedErrors[0]).(AppError).Unwrap().Error(), "nil map")
+}
+
+func TestRecoveryMiddleware_NoPanic(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(NewErrorHandler(&mockAppLoggerInterface{}).RecoveryMiddleware())
+	router.GET("/orders/:id", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/orders/42", nil))
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+}
+
+func TestRecoveryMiddleware_AfterWrite(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockLogger := &mockAppLoggerInterface{}
+	router := gin.New()
+	router.Use(NewErrorHandler(mockLogger).RecoveryMiddleware())
+	router.GET("/orders", func(c *gin.Context) {
+		c.String(http.StatusOK, "partial")
+		panic("stream failed")
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/orders", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "partial", w.Body.String())
+	assert.Len(t, mockLogger.loggedErrors, 1)
+}
+
+func TestRecoveryMiddleware_AbortHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(NewErrorHandler(&mockAppLoggerInterface{}).RecoveryMiddleware())
+	router.GET("/orders", func(c *gin.Context) {
+		panic(http.ErrAbortHandler)
+	})
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/orders", nil))
+	})
+}
@@ -0,0 +1,172 @@
+package errors
+
+import (
+	"sync"
+
+	"google.golang.org/grpc/codes"
+)
+
+// TypeInfo describes an error type
+type TypeInfo struct {
+	HttpStatus int        // HTTP status of the response
+	GrpcCode   codes.Code // gRPC status code
+	Message    string     // default message for the user
+	Code       string     // machine-readable application error code
+}
+
+var (
+	errorCodes = map[ErrorType]string{
+		NoType:             "APP_ERROR",
+		BadRequest:         "BAD_REQUEST",
+		Unauthorized:       "UNAUTHORIZED",
+		Forbidden:          "FORBIDDEN",
+		NotFound:           "NOT_FOUND",
+		RequestTimeout:     "REQUEST_TIMEOUT",
+		Conflict:           "CONFLICT",
+		ServiceUnavailable: "SERVICE_UNAVAILABLE",
+	}
+
+	errorGrpcCodes = map[ErrorType]codes.Code{
+		NoType:             codes.Internal,
+		BadRequest:         codes.InvalidArgument,
+		Unauthorized:       codes.Unauthenticated,
+		Forbidden:          codes.PermissionDenied,
+		NotFound:           codes.NotFound,
+		RequestTimeout:     codes.DeadlineExceeded,
+		Conflict:           codes.AlreadyExists,
+		ServiceUnavailable: codes.Unavailable,
+	}
+
+	registry = newTypeRegistry()
+)
+
+type typeRegistry struct {
+	mu    sync.RWMutex
+	types map[ErrorType]TypeInfo
+	next  ErrorType
+}
+
+func newTypeRegistry() *typeRegistry {
+	r := &typeRegistry{types: make(map[ErrorType]TypeInfo)}
+	for errorType, status := range errorStatuses {
+		r.types[errorType] = TypeInfo{
+			HttpStatus: status,
+			GrpcCode:   errorGrpcCodes[errorType],
+			Message:    errorMessages[errorType],
+			Code:       errorCodes[errorType],
+		}
+		if errorType >= r.next {
+			r.next = errorType + 1
+		}
+	}
+
+	return r
+}
+
+// RegisterType registers a new service error type.
+// Empty fields of the description are taken from NoType. A type without its own
+// Code shares APP_ERROR with NoType and can not be restored by FromGrpcStatus
+//
+//	var PaymentRequired = errors.RegisterType(errors.TypeInfo{
+//		HttpStatus: http.StatusPaymentRequired,
+//		GrpcCode:   codes.FailedPrecondition,
+//		Message:    "Требуется оплата.",
+//		Code:       "PAYMENT_REQUIRED",
+//	})
+func RegisterType(info TypeInfo) ErrorType {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	errorType := registry.next
+	registry.next++
+	registry.types[errorType] = registry.withDefaults(info)
+
+	return errorType
+}
+
+// SetTypeInfo replaces the description of a registered or built-in error type
+func SetTypeInfo(errorType ErrorType, info TypeInfo) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+
+	if _, ok := registry.types[errorType]; ok {
+		registry.types[errorType] = registry.withDefaults(info)
+	}
+}
+
+// IsRegistered reports whether the error type is built-in or registered with RegisterType
+func (t ErrorType) IsRegistered() bool {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	_, ok := registry.types[t]
+	return ok
+}
+
+// Info returns the description of the error type, or the description of NoType for an unregistered type
+func (t ErrorType) Info() TypeInfo {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	if info, ok := registry.types[t]; ok {
+		return info
+	}
+
+	return registry.types[NoType]
+}
+
+// typeByCode returns the error type with the given application code.
+// A code shared by several types, e.g. inherited from NoType, is ambiguous and not resolved
+func typeByCode(code string) (ErrorType, bool) {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	found := NoType
+	matches := 0
+	for errorType, info := range registry.types {
+		if info.Code == code {
+			found = errorType
+			matches++
+		}
+	}
+	if matches != 1 {
+		return NoType, false
+	}
+
+	return found, true
+}
+
+// typeByGrpcCode returns the lowest error type with the given gRPC status code
+func typeByGrpcCode(code codes.Code) ErrorType {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+
+	found := NoType
+	ok := false
+	for errorType, info := range registry.types {
+		if info.GrpcCode == code && (!ok || errorType < found) {
+			found, ok = errorType, true
+		}
+	}
+
+	return found
+}
+
+// withDefaults fills empty fields with the values of NoType, must be called under the lock
+func (r *typeRegistry) withDefaults(info TypeInfo) TypeInfo {
+	defaults := r.types[NoType]
+	if info.HttpStatus == 0 {
+		info.HttpStatus = defaults.HttpStatus
+	}
+	if info.GrpcCode == codes.OK {
+		info.GrpcCode = defaults.GrpcCode
+	}
+	if info.Message == "" {
+		info.Message = defaults.Message
+	}
+	if info.Code == "" {
+		info.Code = defaults.Code
+	}
+
+	return info
+}
@@ -0,0 +1,163 @@
+package errors
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/AeroAgency/golang-helpers-lib/requestid"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+const (
+	problemContentType = "application/problem+json"
+	traceIdHeader      = "X-Trace-Id"
+	violationsField    = "violations"
+	errorsField        = "errors"
+)
+
+// Problem describes an error for the response to the client
+type Problem struct {
+	Status     int                    // HTTP status
+	Title      string                 // HTTP status text
+	Code       string                 // application error code
+	Message    string                 // message for the user
+	Debug      string                 // error text, omitted in production mode
+	Instance   string                 // request path
+	TraceId    string                 // trace id
+	RequestId  string                 // request id
+	Extensions map[string]interface{} // additional response fields
+}
+
+// ProblemItem describes one of the errors of a MultiError
+type ProblemItem struct {
+	Status     int         `json:"status"`
+	Code       string      `json:"code"`
+	Message    string      `json:"message"`
+	Violations []Violation `json:"violations,omitempty"`
+}
+
+// Renderer writes the error response
+type Renderer interface {
+	Render(c *gin.Context, problem Problem)
+}
+
+// NewProblem describes the error by its type. Field violations are passed
+// in the violations extension, the errors of a MultiError are listed in the errors extension
+func NewProblem(err error) Problem {
+	info := GetType(err).Info()
+	problem := Problem{
+		Status:  info.HttpStatus,
+		Title:   http.StatusText(info.HttpStatus),
+		Code:    info.Code,
+		Message: info.Message,
+		Debug:   err.Error(),
+	}
+	if violations := GetViolations(err); len(violations) > 0 {
+		problem.Extensions = map[string]interface{}{violationsField: violations}
+	}
+	var multi *MultiError
+	if errors.As(err, &multi) {
+		if problem.Extensions == nil {
+			problem.Extensions = map[string]interface{}{}
+		}
+		problem.Extensions[errorsField] = problemItems(multi)
+	}
+
+	return problem
+}
+
+func problemItems(multi *MultiError) []ProblemItem {
+	items := make([]ProblemItem, 0, len(multi.Errors()))
+	for _, err := range multi.Errors() {
+		info := GetType(err).Info()
+		items = append(items, ProblemItem{
+			Status:     info.HttpStatus,
+			Code:       info.Code,
+			Message:    info.Message,
+			Violations: GetViolations(err),
+		})
+	}
+
+	return items
+}
+
+// WithRequest adds the request path, the trace id from the response header
+// and the request id
+func (p Problem) WithRequest(c *gin.Context) Problem {
+	if c.Request != nil && c.Request.URL != nil {
+		p.Instance = c.Request.URL.Path
+	}
+	if c.Writer != nil {
+		p.TraceId = c.Writer.Header().Get(traceIdHeader)
+	}
+	p.RequestId = requestid.FromGin(c).RequestId
+
+	return p
+}
+
+// LegacyRenderer responds in the {error, code, message, debug} format, used by default
+type LegacyRenderer struct{}
+
+func (r LegacyRenderer) Render(c *gin.Context, problem Problem) {
+	c.JSON(problem.Status, legacyResponseError(problem))
+}
+
+// ProblemRenderer responds in the application/problem+json format (RFC 7807).
+// The type field is built from TypeBaseUri and the error code, "about:blank" if TypeBaseUri is empty
+type ProblemRenderer struct {
+	TypeBaseUri string
+}
+
+func (r ProblemRenderer) Render(c *gin.Context, problem Problem) {
+	body := make(map[string]interface{}, len(problem.Extensions)+8)
+	for key, value := range problem.Extensions {
+		body[key] = value
+	}
+
+	body["type"] = "about:blank"
+	if r.TypeBaseUri != "" && problem.Code != "" {
+		body["type"] = r.TypeBaseUri + strings.ToLower(problem.Code)
+	}
+	body["title"] = problem.Title
+	body["status"] = problem.Status
+	body["detail"] = problem.Message
+	if problem.Code != "" {
+		body["code"] = problem.Code
+	}
+	if problem.Instance != "" {
+		body["instance"] = problem.Instance
+	}
+	if problem.TraceId != "" {
+		body["traceId"] = problem.TraceId
+	}
+	if problem.RequestId != "" {
+		body["requestId"] = problem.RequestId
+	}
+	if problem.Debug != "" {
+		body["debug"] = problem.Debug
+	}
+
+	c.Header("Content-Type", problemContentType)
+	c.JSON(problem.Status, body)
+}
+
+func legacyResponseError(problem Problem) responseError {
+	response := responseError{
+		Error:     problem.Title,
+		Code:      problem.Code,
+		Message:   problem.Message,
+		RequestId: problem.RequestId,
+	}
+	if violations, ok := problem.Extensions[violationsField].([]Violation); ok {
+		response.Violations = violations
+	}
+	if items, ok := problem.Extensions[errorsField].([]ProblemItem); ok {
+		response.Errors = items
+	}
+	if problem.Debug != "" {
+		response.Debug = problem.Debug
+	}
+
+	return response
+}
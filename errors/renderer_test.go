@@ -0,0 +1,79 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AeroAgency/golang-helpers-lib/requestid"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func handle(h *ErrorHandler, err error) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/orders/42", nil)
+	c.Header(traceIdHeader, "trace-1")
+	c.Set("tracer", &mockTracerAdapter{})
+
+	h.HandleError(c, err)
+
+	return w
+}
+
+func TestHandleError_ProblemRenderer(t *testing.T) {
+	h := NewErrorHandler(&mockAppLoggerInterface{}).SetRenderer(ProblemRenderer{TypeBaseUri: "https://example.com/problems/"})
+
+	w := handle(h, NotFound.New("order 42 not found"))
+
+	var body map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, problemContentType, w.Header().Get("Content-Type"))
+	assert.Equal(t, map[string]interface{}{
+		"type":     "https://example.com/problems/not_found",
+		"title":    http.StatusText(http.StatusNotFound),
+		"status":   float64(http.StatusNotFound),
+		"detail":   errorMessages[NotFound],
+		"code":     "NOT_FOUND",
+		"instance": "/orders/42",
+		"traceId":  "trace-1",
+		"debug":    "order 42 not found",
+	}, body)
+}
+
+func TestHandleError_Production(t *testing.T) {
+	h := NewErrorHandler(&mockAppLoggerInterface{}).SetProduction(true)
+
+	w := handle(h, BadRequest.New("field a is required"))
+
+	var body responseError
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Nil(t, body.Debug)
+	assert.Equal(t, errorMessages[BadRequest], body.Message)
+
+	h.SetRenderer(ProblemRenderer{})
+	w = handle(h, BadRequest.New("field a is required"))
+
+	var problem map[string]interface{}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.NotContains(t, problem, "debug")
+	assert.Equal(t, "about:blank", problem["type"])
+}
+
+func TestHandleError_RequestId(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/orders/42", nil)
+	c.Set(requestid.GinKey, requestid.Ids{RequestId: "req-1", CorrelationId: "chain-1"})
+
+	NewErrorHandler(&mockAppLoggerInterface{}).HandleError(c, NotFound.New("order not found"))
+
+	var body responseError
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "req-1", body.RequestId)
+}
@@ -0,0 +1,237 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Translator holds error message catalogs by locale.
+// Messages are keyed by Trans.Msg and by error type codes (TypeInfo.Code),
+// Trans.Params replace the {0}, {1}, ... placeholders
+type Translator struct {
+	mu        sync.RWMutex
+	catalogs  map[string]map[string]string
+	fallbacks []string
+}
+
+// NewTranslator creates a Translator. The fallbacks locales are used when a message is missing in the requested ones
+func NewTranslator(fallbacks ...string) *Translator {
+	return &Translator{
+		catalogs:  make(map[string]map[string]string),
+		fallbacks: normalizeLocales(fallbacks),
+	}
+}
+
+// Add adds messages of the locale
+func (t *Translator) Add(locale string, messages map[string]string) *Translator {
+	locale = normalizeLocale(locale)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	catalog, ok := t.catalogs[locale]
+	if !ok {
+		catalog = make(map[string]string)
+		t.catalogs[locale] = catalog
+	}
+	for key, message := range messages {
+		catalog[key] = message
+	}
+
+	return t
+}
+
+// LoadFile loads messages of the locale from a JSON or YAML file.
+// Nested keys are joined with a dot: {"order": {"not_found": "..."}} -> "order.not_found"
+func (t *Translator) LoadFile(locale, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var raw interface{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &raw)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	default:
+		return fmt.Errorf("unsupported translation file format: %s", path)
+	}
+	if err != nil {
+		return fmt.Errorf("translation file %s: %w", path, err)
+	}
+
+	messages := make(map[string]string)
+	if err := flattenMessages("", raw, messages); err != nil {
+		return fmt.Errorf("translation file %s: %w", path, err)
+	}
+	t.Add(locale, messages)
+
+	return nil
+}
+
+// LoadDir loads catalogs from the files of the directory, the file name sets the locale: ru.json, en.yaml
+func (t *Translator) LoadDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".json" && ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		locale := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		if err := t.LoadFile(locale, filepath.Join(dir, entry.Name())); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Translate returns the message of the key in the first matching locale, and that locale.
+// Lookup order: locales, their base languages (en-US -> en), the fallbacks locales
+func (t *Translator) Translate(locales []string, key string, params ...string) (string, string, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	for _, locale := range t.candidates(locales) {
+		if message, ok := t.catalogs[locale][key]; ok {
+			return interpolate(message, params), locale, true
+		}
+	}
+
+	return "", "", false
+}
+
+// candidates returns the locales in lookup order without duplicates
+func (t *Translator) candidates(locales []string) []string {
+	var candidates []string
+	seen := make(map[string]bool)
+	add := func(locale string) {
+		if locale != "" && !seen[locale] {
+			seen[locale] = true
+			candidates = append(candidates, locale)
+		}
+	}
+
+	for _, locale := range normalizeLocales(locales) {
+		add(locale)
+	}
+	for _, locale := range normalizeLocales(locales) {
+		base, _, _ := strings.Cut(locale, "-")
+		add(base)
+	}
+	for _, locale := range t.fallbacks {
+		add(locale)
+	}
+
+	return candidates
+}
+
+// ParseAcceptLanguage returns the locales of an Accept-Language header in descending order of weight
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		locale string
+		q      float64
+	}
+
+	var languages []weighted
+	for _, part := range strings.Split(header, ",") {
+		locale, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		locale = strings.TrimSpace(locale)
+		if locale == "" || locale == "*" {
+			continue
+		}
+
+		q := 1.0
+		if params = strings.TrimSpace(params); strings.HasPrefix(params, "q=") {
+			parsed, err := strconv.ParseFloat(strings.TrimPrefix(params, "q="), 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q > 0 {
+			languages = append(languages, weighted{locale: locale, q: q})
+		}
+	}
+
+	sort.SliceStable(languages, func(i, j int) bool {
+		return languages[i].q > languages[j].q
+	})
+
+	locales := make([]string, 0, len(languages))
+	for _, language := range languages {
+		locales = append(locales, language.locale)
+	}
+
+	return locales
+}
+
+func interpolate(message string, params []string) string {
+	if len(params) == 0 {
+		return message
+	}
+
+	replacements := make([]string, 0, len(params)*2)
+	for i, param := range params {
+		replacements = append(replacements, "{"+strconv.Itoa(i)+"}", param)
+	}
+
+	return strings.NewReplacer(replacements...).Replace(message)
+}
+
+func flattenMessages(prefix string, value interface{}, messages map[string]string) error {
+	join := func(key string) string {
+		if prefix == "" {
+			return key
+		}
+		return prefix + "." + key
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for key, nested := range value {
+			if err := flattenMessages(join(key), nested, messages); err != nil {
+				return err
+			}
+		}
+	case map[interface{}]interface{}:
+		for key, nested := range value {
+			if err := flattenMessages(join(fmt.Sprint(key)), nested, messages); err != nil {
+				return err
+			}
+		}
+	case string:
+		messages[prefix] = value
+	default:
+		return fmt.Errorf("the '%s' message must be a string", prefix)
+	}
+
+	return nil
+}
+
+func normalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+func normalizeLocales(locales []string) []string {
+	normalized := make([]string, 0, len(locales))
+	for _, locale := range locales {
+		normalized = append(normalized, normalizeLocale(locale))
+	}
+
+	return normalized
+}
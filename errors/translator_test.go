@@ -0,0 +1,77 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	assert.Equal(t, []string{"en-US", "ru", "en"}, ParseAcceptLanguage("ru;q=0.8, en-US, en;q=0.5, de;q=0, *"))
+	assert.Empty(t, ParseAcceptLanguage(""))
+}
+
+func TestTranslator(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "en.json"), []byte(`{"order": {"not_found": "Order {0} not found"}, "NOT_FOUND": "Not found."}`), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "ru.yaml"), []byte("order:\n  not_found: Заказ {0} не найден\n"), 0o644))
+
+	translator := NewTranslator("ru")
+	assert.NoError(t, translator.LoadDir(dir))
+
+	message, locale, ok := translator.Translate([]string{"en-US"}, "order.not_found", "42")
+	assert.True(t, ok)
+	assert.Equal(t, "en", locale)
+	assert.Equal(t, "Order 42 not found", message)
+
+	message, locale, ok = translator.Translate([]string{"de"}, "order.not_found", "42")
+	assert.True(t, ok)
+	assert.Equal(t, "ru", locale)
+	assert.Equal(t, "Заказ 42 не найден", message)
+
+	_, _, ok = translator.Translate([]string{"en"}, "order.unknown")
+	assert.False(t, ok)
+
+	assert.Error(t, translator.LoadFile("en", filepath.Join(dir, "missing.json")))
+}
+
+func TestHandleError_Translated(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	translator := NewTranslator("ru").
+		Add("en", map[string]string{"order.not_found": "Order {0} not found", "BAD_REQUEST": "Bad request."}).
+		Add("ru", map[string]string{"order.not_found": "Заказ {0} не найден"})
+	h := NewErrorHandler(&mockAppLoggerInterface{}).SetTranslator(translator)
+
+	cases := []struct {
+		err      error
+		language string
+		message  string
+		locale   string
+	}{
+		{NotFound.New("not found").T("order.not_found", "42"), "en-US,en;q=0.9", "Order 42 not found", "en"},
+		{NotFound.New("not found").T("order.not_found", "42"), "de", "Заказ 42 не найден", "ru"},
+		{BadRequest.New("bad request"), "en", "Bad request.", "en"},
+		{Conflict.New("conflict"), "en", errorMessages[Conflict], ""},
+	}
+
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+		c.Request.Header.Set("Accept-Language", tc.language)
+		c.Set("tracer", &mockTracerAdapter{})
+
+		h.HandleError(c, tc.err)
+
+		var body responseError
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, tc.message, body.Message)
+		assert.Equal(t, tc.locale, w.Header().Get("Content-Language"))
+	}
+}
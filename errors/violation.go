@@ -0,0 +1,41 @@
+package errors
+
+// Violation is a broken validation rule of a field
+type Violation struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule,omitempty"`
+	Message string `json:"message"`
+}
+
+// NewValidationError creates a BadRequest error with the field violations
+func NewValidationError(msg string, violations ...Violation) AppError {
+	return BadRequest.New(msg).WithViolations(violations...)
+}
+
+// WithViolations returns a copy of the error with the field violations added
+func (e AppError) WithViolations(violations ...Violation) AppError {
+	list := append(append([]Violation(nil), GetViolations(e)...), violations...)
+	e.violations = &list
+	return e
+}
+
+// GetViolations returns the field violations of the first AppError in the wrap chain
+func GetViolations(err error) []Violation {
+	if customErr, ok := appErrorOf(err); ok {
+		if customErr.violations != nil {
+			return *customErr.violations
+		}
+	}
+
+	return nil
+}
+
+// ViolationsByField groups the messages of the violations by field
+func ViolationsByField(violations []Violation) map[string][]string {
+	fields := make(map[string][]string)
+	for _, violation := range violations {
+		fields[violation.Field] = append(fields[violation.Field], violation.Message)
+	}
+
+	return fields
+}
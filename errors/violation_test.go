@@ -0,0 +1,39 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidationError(t *testing.T) {
+	violations := []Violation{
+		{Field: "email", Rule: "required", Message: "The email field is required"},
+		{Field: "name", Rule: "min", Message: "The name field must be minimum 3 char"},
+		{Field: "name", Rule: "alpha", Message: "The name may only contain letters"},
+	}
+	err := fmt.Errorf("handler: %w", NewValidationError("The email field is required", violations...))
+
+	assert.Equal(t, BadRequest, GetType(err))
+	assert.Equal(t, violations, GetViolations(err))
+	assert.Equal(t, map[string][]string{
+		"email": {"The email field is required"},
+		"name":  {"The name field must be minimum 3 char", "The name may only contain letters"},
+	}, ViolationsByField(GetViolations(err)))
+
+	w := handle(NewErrorHandler(&mockAppLoggerInterface{}), err)
+	var body responseError
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, violations, body.Violations)
+
+	w = handle(NewErrorHandler(&mockAppLoggerInterface{}).SetRenderer(ProblemRenderer{}), err)
+	var problem struct {
+		Violations []Violation `json:"violations"`
+	}
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, violations, problem.Violations)
+
+	assert.Nil(t, GetViolations(BadRequest.New("bad request")))
+}
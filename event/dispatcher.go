@@ -1,51 +1,544 @@
 package event
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// UnregisteredPolicy определяет поведение при отправке события без слушателей
+type UnregisteredPolicy int
+
+const (
+	// PanicOnUnregistered паника при отправке незарегистрированного события (по умолчанию)
+	PanicOnUnregistered UnregisteredPolicy = iota
+	// ErrorOnUnregistered возврат ErrNotRegistered при отправке незарегистрированного события
+	ErrorOnUnregistered
+	// IgnoreUnregistered незарегистрированное событие отбрасывается без ошибки
+	IgnoreUnregistered
 )
 
 // диспетчер событий
 type Dispatcher struct {
-	jobs   chan job
-	events map[Name]Listener
+	mu                 sync.RWMutex
+	subscriptions      []*subscription
+	lastID             uint64
+	errorHandler       ErrorHandler
+	unregisteredPolicy UnregisteredPolicy
+	store              Store
+	types              map[Name]reflect.Type
+	observers          []Observer
+
+	queueMu sync.Mutex
+	queued  *sync.Cond
+	queue   []job
+	closed  bool
+	done    chan struct{}
 }
 
+// ErrorHandler получает ошибки слушателей при асинхронной отправке события
+type ErrorHandler func(ctx context.Context, name Name, err error)
+
 // конструктор
 func NewDispatcher() *Dispatcher {
 	d := &Dispatcher{
-		jobs:   make(chan job),
-		events: make(map[Name]Listener),
+		types: make(map[Name]reflect.Type),
+		done:  make(chan struct{}),
 	}
+	d.queued = sync.NewCond(&d.queueMu)
 
 	go d.consume()
 
 	return d
 }
 
-// регистрация событий и слушателей событий
+// установка обработчика ошибок слушателей при асинхронной отправке события
+func (d *Dispatcher) SetErrorHandler(handler ErrorHandler) *Dispatcher {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.errorHandler = handler
+	return d
+}
+
+// установка поведения при отправке события без слушателей
+func (d *Dispatcher) SetUnregisteredPolicy(policy UnregisteredPolicy) *Dispatcher {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.unregisteredPolicy = policy
+	return d
+}
+
+// установка хранилища событий. События сохраняются перед доставкой
+// и отмечаются доставленными после успешного завершения всех слушателей.
+// Недоставленные события повторно отправляются через ReplayPending
+func (d *Dispatcher) SetStore(store Store) *Dispatcher {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.store = store
+	return d
+}
+
+// добавление наблюдателя за отправкой событий и работой слушателей
+func (d *Dispatcher) AddObserver(observer Observer) *Dispatcher {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.observers = append(d.observers, observer)
+	return d
+}
+
+// регистрация типа события для восстановления из хранилища.
+// Без регистрации слушатели повторно отправленного события получат json.RawMessage.
+// Subscribe регистрирует тип события автоматически
+func (d *Dispatcher) RegisterType(name Name, event interface{}) {
+	d.registerType(name, reflect.TypeOf(event))
+}
+
+func (d *Dispatcher) registerType(name Name, t reflect.Type) {
+	if t == nil || t.Kind() == reflect.Interface {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.types[name] = t
+}
+
+// регистрация слушателя на события с приоритетом по умолчанию.
+// На одно событие может быть зарегистрировано несколько слушателей,
+// в качестве имени допускается шаблон (см. Subscribe)
 func (d *Dispatcher) Register(listener Listener, names ...Name) error {
 	for _, name := range names {
-		if _, ok := d.events[name]; ok {
-			return fmt.Errorf("the '%s' event is already registered", name)
+		if _, err := d.Subscribe(name, listener, DefaultPriority); err != nil {
+			return err
 		}
+	}
+
+	return nil
+}
 
-		d.events[name] = listener
+// регистрация слушателя, принимающего контекст, на события с приоритетом по умолчанию
+func (d *Dispatcher) RegisterContext(listener ContextListener, names ...Name) error {
+	for _, name := range names {
+		if _, err := d.SubscribeContext(name, listener, DefaultPriority); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
+// подписка слушателя на событие или шаблон событий.
+// В шаблоне сегменты имени разделяются точкой: "*" соответствует ровно одному
+// сегменту ("order.*"), "**" - любому количеству сегментов ("order.**").
+// Слушатели с большим приоритетом вызываются раньше, при равном приоритете -
+// в порядке подписки
+func (d *Dispatcher) Subscribe(pattern Name, listener Listener, priority int) (*Subscription, error) {
+	if listener == nil {
+		return nil, fmt.Errorf("the listener for the '%s' event must not be nil", pattern)
+	}
+
+	return d.SubscribeContext(pattern, listenerAdapter{listener: listener}, priority)
+}
+
+// подписка слушателя, принимающего контекст, на событие или шаблон событий
+func (d *Dispatcher) SubscribeContext(pattern Name, listener ContextListener, priority int) (*Subscription, error) {
+	if pattern == "" {
+		return nil, fmt.Errorf("the event name must not be empty")
+	}
+	if listener == nil {
+		return nil, fmt.Errorf("the listener for the '%s' event must not be nil", pattern)
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.lastID++
+	d.subscriptions = append(d.subscriptions, &subscription{
+		id:       d.lastID,
+		pattern:  pattern,
+		priority: priority,
+		listener: listener,
+	})
+	sort.SliceStable(d.subscriptions, func(i, j int) bool {
+		return d.subscriptions[i].priority > d.subscriptions[j].priority
+	})
+
+	return &Subscription{id: d.lastID, dispatcher: d}, nil
+}
+
 // отправка события
-func (d *Dispatcher) Dispatch(name Name, event interface{}) {
-	if _, ok := d.events[name]; !ok {
-		panic(fmt.Sprintf("the '%s' event is not registered", name))
+func (d *Dispatcher) Dispatch(name Name, event interface{}) error {
+	return d.DispatchContext(context.Background(), name, event)
+}
+
+// асинхронная отправка события с контекстом. Событие ставится в очередь без ожидания
+// слушателей, контекст передается слушателям как есть, ошибки слушателей
+// передаются обработчику, установленному через SetErrorHandler
+func (d *Dispatcher) DispatchContext(ctx context.Context, name Name, event interface{}) error {
+	return d.enqueue(ctx, name, event, d.getUnregisteredPolicy())
+}
+
+// enqueue ставит событие в очередь, policy определяет поведение при отсутствии слушателей
+func (d *Dispatcher) enqueue(ctx context.Context, name Name, event interface{}, policy UnregisteredPolicy) error {
+	if d.isClosed() {
+		return ErrClosed
+	}
+	job, ok, err := d.newJob(ctx, name, event, policy)
+	if !ok {
+		return err
+	}
+	if err := d.persist(&job); err != nil {
+		return err
+	}
+
+	d.queueMu.Lock()
+	if d.closed {
+		d.queueMu.Unlock()
+		return ErrClosed
+	}
+	d.queue = append(d.queue, job)
+	depth := len(d.queue)
+	d.queued.Signal()
+	d.queueMu.Unlock()
+
+	for _, observer := range d.getObservers() {
+		observer.Dispatched(ctx, name)
+		observer.QueueDepth(depth)
+	}
+
+	return nil
+}
+
+// синхронная отправка события: слушатели вызываются в текущей горутине,
+// возвращается *DispatchError с ошибками всех слушателей, завершившихся неудачно
+func (d *Dispatcher) DispatchSync(ctx context.Context, name Name, event interface{}) error {
+	if d.isClosed() {
+		return ErrClosed
+	}
+
+	job, ok, err := d.newJob(ctx, name, event, d.getUnregisteredPolicy())
+	if !ok {
+		return err
+	}
+	if err := d.persist(&job); err != nil {
+		return err
+	}
+	for _, observer := range d.getObservers() {
+		observer.Dispatched(ctx, name)
+	}
+
+	return d.process(job)
+}
+
+// ReplayPending повторно отправляет события, не доставленные всем слушателям,
+// например после аварийного завершения. Вызывается после подписки слушателей
+func (d *Dispatcher) ReplayPending(ctx context.Context) error {
+	return d.Replay(ctx, Filter{Undelivered: true})
+}
+
+// Replay синхронно отправляет слушателям события из хранилища, удовлетворяющие фильтру.
+// События без слушателей пропускаются, ошибки слушателей не прерывают повтор
+// и возвращаются в *ReplayError
+func (d *Dispatcher) Replay(ctx context.Context, filter Filter) error {
+	d.mu.RLock()
+	store := d.store
+	d.mu.RUnlock()
+	if store == nil {
+		return errors.New("the event store is not set")
+	}
+
+	records, err := store.Find(ctx, filter)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, record := range records {
+		listeners := d.listeners(record.Name)
+		if len(listeners) == 0 {
+			continue
+		}
+
+		event, err := d.decode(record)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("the '%s' event %s: %w", record.Name, record.ID, err))
+			continue
+		}
+
+		err = d.process(job{ctx: ctx, eventName: record.Name, eventType: event, listeners: listeners, recordID: record.ID})
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return &ReplayError{Errors: errs}
+	}
+
+	return nil
+}
+
+// Close прекращает прием событий и дожидается доставки событий из очереди.
+// Если контекст завершится раньше, недоставленные события отбрасываются
+// и возвращаются в *DroppedError. Повторный вызов возвращает ErrClosed
+func (d *Dispatcher) Close(ctx context.Context) error {
+	d.queueMu.Lock()
+	if d.closed {
+		d.queueMu.Unlock()
+		return ErrClosed
+	}
+	d.closed = true
+	d.queued.Broadcast()
+	d.queueMu.Unlock()
+
+	select {
+	case <-d.done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	d.queueMu.Lock()
+	dropped := d.queue
+	d.queue = nil
+	d.queueMu.Unlock()
+
+	for _, observer := range d.getObservers() {
+		observer.QueueDepth(0)
+	}
+
+	if len(dropped) == 0 {
+		return nil
+	}
+
+	droppedErr := &DroppedError{Err: ctx.Err()}
+	for _, job := range dropped {
+		droppedErr.Events = append(droppedErr.Events, Envelope{Name: job.eventName, Event: job.eventType})
+	}
+
+	return droppedErr
+}
+
+// newJob возвращает задание на доставку события. Если задание не создано,
+// возвращается ошибка согласно policy
+func (d *Dispatcher) newJob(ctx context.Context, name Name, event interface{}, policy UnregisteredPolicy) (job, bool, error) {
+	listeners := d.listeners(name)
+	if len(listeners) == 0 {
+		switch policy {
+		case IgnoreUnregistered:
+			return job{}, false, nil
+		case ErrorOnUnregistered:
+			return job{}, false, fmt.Errorf("the '%s' event: %w", name, ErrNotRegistered)
+		default:
+			panic(fmt.Sprintf("the '%s' event is not registered", name))
+		}
 	}
 
-	d.jobs <- job{eventName: name, eventType: event}
+	return job{ctx: ctx, eventName: name, eventType: event, listeners: listeners}, true, nil
+}
+
+func (d *Dispatcher) isClosed() bool {
+	d.queueMu.Lock()
+	defer d.queueMu.Unlock()
+
+	return d.closed
+}
+
+func (d *Dispatcher) getUnregisteredPolicy() UnregisteredPolicy {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.unregisteredPolicy
+}
+
+// listeners возвращает слушателей события в порядке вызова
+func (d *Dispatcher) listeners(name Name) []ContextListener {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	var listeners []ContextListener
+	for _, s := range d.subscriptions {
+		if match(s.pattern, name) {
+			listeners = append(listeners, s.listener)
+		}
+	}
+
+	return listeners
+}
+
+func (d *Dispatcher) getObservers() []Observer {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return d.observers
+}
+
+func (d *Dispatcher) unsubscribe(id uint64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for i, s := range d.subscriptions {
+		if s.id == id {
+			d.subscriptions = append(d.subscriptions[:i], d.subscriptions[i+1:]...)
+			return
+		}
+	}
 }
 
 func (d *Dispatcher) consume() {
-	for job := range d.jobs {
-		d.events[job.eventName].Listen(job.eventType)
+	defer close(d.done)
+
+	for {
+		job, ok := d.next()
+		if !ok {
+			return
+		}
+
+		if err := d.process(job); err != nil {
+			d.handleError(job.ctx, job.eventName, err)
+		}
+	}
+}
+
+// handleError передает ошибку асинхронной доставки обработчику ошибок
+func (d *Dispatcher) handleError(ctx context.Context, name Name, err error) {
+	d.mu.RLock()
+	handler := d.errorHandler
+	d.mu.RUnlock()
+	if handler != nil {
+		handler(ctx, name, err)
+	}
+}
+
+// next ожидает следующее событие из очереди. Возвращает false,
+// когда диспетчер закрыт и очередь пуста
+func (d *Dispatcher) next() (job, bool) {
+	d.queueMu.Lock()
+	for len(d.queue) == 0 {
+		if d.closed {
+			d.queueMu.Unlock()
+			return job{}, false
+		}
+		d.queued.Wait()
+	}
+
+	next := d.queue[0]
+	d.queue[0] = job{}
+	d.queue = d.queue[1:]
+	depth := len(d.queue)
+	d.queueMu.Unlock()
+
+	for _, observer := range d.getObservers() {
+		observer.QueueDepth(depth)
+	}
+
+	return next, true
+}
+
+// persist сохраняет событие в хранилище, если оно установлено
+func (d *Dispatcher) persist(job *job) error {
+	d.mu.RLock()
+	store := d.store
+	d.mu.RUnlock()
+	if store == nil {
+		return nil
+	}
+
+	payload, err := json.Marshal(job.eventType)
+	if err != nil {
+		return fmt.Errorf("the '%s' event can not be stored: %w", job.eventName, err)
+	}
+
+	job.recordID = uuid.NewV4().String()
+
+	return store.Save(job.ctx, Record{
+		ID:        job.recordID,
+		Name:      job.eventName,
+		Payload:   payload,
+		CreatedAt: time.Now(),
+	})
+}
+
+// decode восстанавливает событие из записи хранилища
+func (d *Dispatcher) decode(record Record) (interface{}, error) {
+	d.mu.RLock()
+	t, ok := d.types[record.Name]
+	d.mu.RUnlock()
+	if !ok {
+		return record.Payload, nil
 	}
+
+	event := reflect.New(t)
+	if err := json.Unmarshal(record.Payload, event.Interface()); err != nil {
+		return nil, err
+	}
+
+	return event.Elem().Interface(), nil
+}
+
+// process доставляет событие и отмечает доставку в хранилище
+func (d *Dispatcher) process(job job) error {
+	if err := d.deliver(job); err != nil {
+		return err
+	}
+	if job.recordID == "" {
+		return nil
+	}
+
+	d.mu.RLock()
+	store := d.store
+	d.mu.RUnlock()
+
+	return store.MarkDelivered(detachedContext{job.ctx}, job.recordID, time.Now())
+}
+
+func (d *Dispatcher) deliver(job job) error {
+	var errs []error
+	for _, listener := range job.listeners {
+		if err := d.invoke(job, listener); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return &DispatchError{Name: job.eventName, Errors: errs}
+	}
+
+	return nil
+}
+
+// invoke вызывает слушателя, уведомляя наблюдателей о начале и завершении его работы
+func (d *Dispatcher) invoke(job job, listener ContextListener) error {
+	observers := d.getObservers()
+	if len(observers) == 0 {
+		return listener.ListenContext(job.ctx, job.eventType)
+	}
+
+	ctx := job.ctx
+	contexts := make([]context.Context, len(observers))
+	for i, observer := range observers {
+		ctx = observer.ListenerStarted(ctx, job.eventName)
+		contexts[i] = ctx
+	}
+
+	start := time.Now()
+	err := listener.ListenContext(ctx, job.eventType)
+	duration := time.Since(start)
+
+	for i := len(observers) - 1; i >= 0; i-- {
+		observers[i].ListenerFinished(contexts[i], job.eventName, duration, err)
+	}
+
+	return err
 }
@@ -0,0 +1,15 @@
+package event
+
+import "context"
+
+// DispatcherInterface Интерфейс отправки событий. Позволяет подменять диспетчер в тестах (см. пакет eventtest)
+type DispatcherInterface interface {
+	// Dispatch асинхронная отправка события
+	Dispatch(name Name, event interface{}) error
+	// DispatchContext асинхронная отправка события с контекстом
+	DispatchContext(ctx context.Context, name Name, event interface{}) error
+	// DispatchSync синхронная отправка события
+	DispatchSync(ctx context.Context, name Name, event interface{}) error
+}
+
+var _ DispatcherInterface = (*Dispatcher)(nil)
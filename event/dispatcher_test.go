@@ -0,0 +1,235 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type listenerMock struct {
+	id    string
+	calls chan string
+}
+
+func (l listenerMock) Listen(event interface{}) {
+	l.calls <- l.id
+}
+
+func receive(t *testing.T, calls chan string, count int) []string {
+	t.Helper()
+	var got []string
+	for i := 0; i < count; i++ {
+		select {
+		case id := <-calls:
+			got = append(got, id)
+		case <-time.After(time.Second):
+			t.Fatalf("expected %d listener calls, got %v", count, got)
+		}
+	}
+	return got
+}
+
+func TestDispatcher_MultipleListenersByPriority(t *testing.T) {
+	calls := make(chan string, 10)
+	d := NewDispatcher()
+
+	assert.NoError(t, d.Register(listenerMock{id: "email", calls: calls}, "order.created"))
+	_, err := d.Subscribe("order.created", listenerMock{id: "audit", calls: calls}, 10)
+	assert.NoError(t, err)
+	_, err = d.Subscribe("order.created", listenerMock{id: "cache", calls: calls}, DefaultPriority)
+	assert.NoError(t, err)
+
+	assert.NoError(t, d.Dispatch("order.created", struct{}{}))
+
+	assert.Equal(t, []string{"audit", "email", "cache"}, receive(t, calls, 3))
+}
+
+func TestDispatcher_Unsubscribe(t *testing.T) {
+	calls := make(chan string, 10)
+	d := NewDispatcher()
+
+	s, err := d.Subscribe("order.created", listenerMock{id: "email", calls: calls}, DefaultPriority)
+	assert.NoError(t, err)
+	assert.NoError(t, d.Register(listenerMock{id: "audit", calls: calls}, "order.created"))
+
+	s.Unsubscribe()
+	s.Unsubscribe()
+	assert.NoError(t, d.Dispatch("order.created", struct{}{}))
+
+	assert.Equal(t, []string{"audit"}, receive(t, calls, 1))
+}
+
+func TestDispatcher_DispatchUnregistered(t *testing.T) {
+	d := NewDispatcher()
+	assert.Panics(t, func() { _ = d.Dispatch("order.created", struct{}{}) })
+
+	d.SetUnregisteredPolicy(ErrorOnUnregistered)
+	assert.ErrorIs(t, d.Dispatch("order.created", struct{}{}), ErrNotRegistered)
+	assert.ErrorIs(t, d.DispatchSync(context.Background(), "order.created", struct{}{}), ErrNotRegistered)
+
+	d.SetUnregisteredPolicy(IgnoreUnregistered)
+	assert.NoError(t, d.Dispatch("order.created", struct{}{}))
+}
+
+type blockingListener struct {
+	started chan struct{}
+	release chan struct{}
+	calls   chan interface{}
+}
+
+func (l blockingListener) Listen(event interface{}) {
+	if l.started != nil {
+		l.started <- struct{}{}
+		<-l.release
+	}
+	l.calls <- event
+}
+
+func TestDispatcher_Close_Drains(t *testing.T) {
+	calls := make(chan interface{}, 10)
+	d := NewDispatcher()
+	assert.NoError(t, d.Register(blockingListener{calls: calls}, "order.created"))
+
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, d.Dispatch("order.created", i))
+	}
+	assert.NoError(t, d.Close(context.Background()))
+	assert.Len(t, calls, 5)
+
+	assert.ErrorIs(t, d.Dispatch("order.created", 5), ErrClosed)
+	assert.ErrorIs(t, d.DispatchSync(context.Background(), "order.created", 5), ErrClosed)
+	assert.ErrorIs(t, d.Close(context.Background()), ErrClosed)
+}
+
+func TestDispatcher_Close_DropsOnDeadline(t *testing.T) {
+	listener := blockingListener{
+		started: make(chan struct{}, 1),
+		release: make(chan struct{}),
+		calls:   make(chan interface{}, 10),
+	}
+	d := NewDispatcher()
+	assert.NoError(t, d.Register(listener, "order.created"))
+
+	assert.NoError(t, d.Dispatch("order.created", 1))
+	<-listener.started
+	assert.NoError(t, d.Dispatch("order.created", 2))
+	assert.NoError(t, d.Dispatch("order.created", 3))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := d.Close(ctx)
+	close(listener.release)
+
+	var droppedErr *DroppedError
+	assert.True(t, errors.As(err, &droppedErr))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, []Envelope{{Name: "order.created", Event: 2}, {Name: "order.created", Event: 3}}, droppedErr.Events)
+}
+
+func TestDispatcher_Subscribe_Validation(t *testing.T) {
+	d := NewDispatcher()
+
+	_, err := d.Subscribe("", listenerMock{}, DefaultPriority)
+	assert.Error(t, err)
+	_, err = d.Subscribe("order.created", nil, DefaultPriority)
+	assert.Error(t, err)
+}
+
+func TestMatch(t *testing.T) {
+	cases := []struct {
+		pattern Name
+		name    Name
+		matched bool
+	}{
+		{"order.created", "order.created", true},
+		{"order.created", "order.deleted", false},
+		{"order.*", "order.created", true},
+		{"order.*", "order", false},
+		{"order.*", "order.item.created", false},
+		{"*.created", "order.created", true},
+		{"order.**", "order", true},
+		{"order.**", "order.item.created", true},
+		{"**.created", "order.item.created", true},
+		{"**", "user.deleted", true},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.matched, match(c.pattern, c.name), "%s ~ %s", c.pattern, c.name)
+	}
+}
+
+type ctxKey struct{}
+
+type contextListenerMock struct {
+	err   error
+	calls chan context.Context
+}
+
+func (l contextListenerMock) ListenContext(ctx context.Context, event interface{}) error {
+	if l.calls != nil {
+		l.calls <- ctx
+	}
+	return l.err
+}
+
+func TestDispatcher_DispatchContext(t *testing.T) {
+	calls := make(chan context.Context, 1)
+	errs := make(chan error, 1)
+	d := NewDispatcher().SetErrorHandler(func(ctx context.Context, name Name, err error) {
+		errs <- err
+	})
+	listenErr := errors.New("listener failed")
+	assert.NoError(t, d.RegisterContext(contextListenerMock{err: listenErr, calls: calls}, "order.created"))
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "trace-id")
+	assert.NoError(t, d.DispatchContext(ctx, "order.created", struct{}{}))
+
+	select {
+	case got := <-calls:
+		assert.Equal(t, "trace-id", got.Value(ctxKey{}))
+	case <-time.After(time.Second):
+		t.Fatal("listener was not called")
+	}
+	select {
+	case err := <-errs:
+		assert.ErrorIs(t, err.(*DispatchError).Errors[0], listenErr)
+	case <-time.After(time.Second):
+		t.Fatal("error handler was not called")
+	}
+}
+
+func TestDispatcher_DispatchSync(t *testing.T) {
+	d := NewDispatcher()
+	first, second := errors.New("first"), errors.New("second")
+	assert.NoError(t, d.RegisterContext(contextListenerMock{err: first}, "order.created"))
+	assert.NoError(t, d.RegisterContext(contextListenerMock{}, "order.created"))
+	assert.NoError(t, d.RegisterContext(contextListenerMock{err: second}, "order.*"))
+
+	err := d.DispatchSync(context.Background(), "order.created", struct{}{})
+
+	var dispatchErr *DispatchError
+	assert.True(t, errors.As(err, &dispatchErr))
+	assert.Equal(t, Name("order.created"), dispatchErr.Name)
+	assert.Equal(t, []error{first, second}, dispatchErr.Errors)
+	assert.True(t, errors.Is(err, second))
+	assert.False(t, errors.Is(err, ErrClosed))
+
+	d = NewDispatcher()
+	assert.NoError(t, d.RegisterContext(contextListenerMock{}, "order.created"))
+	assert.NoError(t, d.DispatchSync(context.Background(), "order.created", struct{}{}))
+}
+
+func TestReplayError_IsAs(t *testing.T) {
+	dropped := &DroppedError{Err: context.DeadlineExceeded}
+	err := fmt.Errorf("replay: %w", &ReplayError{Errors: []error{errors.New("decode"), dropped}})
+
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	var droppedErr *DroppedError
+	assert.True(t, errors.As(err, &droppedErr))
+	assert.Same(t, dropped, droppedErr)
+	assert.False(t, errors.Is(err, ErrNotRegistered))
+}
@@ -0,0 +1,117 @@
+package event
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrClosed возвращается при отправке события в закрытый диспетчер
+	ErrClosed = errors.New("the event dispatcher is closed")
+	// ErrNotRegistered возвращается при отправке события без слушателей с политикой ErrorOnUnregistered
+	ErrNotRegistered = errors.New("the event is not registered")
+)
+
+// Envelope событие вместе с его именем
+type Envelope struct {
+	Name  Name
+	Event interface{}
+}
+
+// DroppedError содержит события, не доставленные до завершения работы диспетчера
+type DroppedError struct {
+	Events []Envelope
+	Err    error
+}
+
+func (e *DroppedError) Error() string {
+	names := make([]string, 0, len(e.Events))
+	for _, event := range e.Events {
+		names = append(names, string(event.Name))
+	}
+
+	return fmt.Sprintf("%d queued events were dropped (%s): %v", len(e.Events), strings.Join(names, ", "), e.Err)
+}
+
+// Unwrap возвращает ошибку контекста, по которой прервана доставка
+func (e *DroppedError) Unwrap() error {
+	return e.Err
+}
+
+// DispatchError содержит ошибки слушателей события
+type DispatchError struct {
+	Name   Name
+	Errors []error
+}
+
+func (e *DispatchError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		messages = append(messages, err.Error())
+	}
+
+	return fmt.Sprintf("the '%s' event listeners failed: %s", e.Name, strings.Join(messages, "; "))
+}
+
+// Unwrap возвращает ошибки слушателей
+func (e *DispatchError) Unwrap() []error {
+	return e.Errors
+}
+
+// Is проверяет ошибки слушателей, errors.Is до Go 1.20 не использует Unwrap() []error
+func (e *DispatchError) Is(target error) bool {
+	return isAny(e.Errors, target)
+}
+
+// As находит первую ошибку слушателя, соответствующую target
+func (e *DispatchError) As(target interface{}) bool {
+	return asAny(e.Errors, target)
+}
+
+// ReplayError содержит ошибки повторной отправки событий из хранилища
+type ReplayError struct {
+	Errors []error
+}
+
+func (e *ReplayError) Error() string {
+	messages := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		messages = append(messages, err.Error())
+	}
+
+	return fmt.Sprintf("%d events failed to replay: %s", len(e.Errors), strings.Join(messages, "; "))
+}
+
+// Unwrap возвращает ошибки повторной отправки
+func (e *ReplayError) Unwrap() []error {
+	return e.Errors
+}
+
+// Is проверяет ошибки повторной отправки
+func (e *ReplayError) Is(target error) bool {
+	return isAny(e.Errors, target)
+}
+
+// As находит первую ошибку повторной отправки, соответствующую target
+func (e *ReplayError) As(target interface{}) bool {
+	return asAny(e.Errors, target)
+}
+
+func isAny(errs []error, target error) bool {
+	for _, err := range errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func asAny(errs []error, target interface{}) bool {
+	for _, err := range errs {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -3,7 +3,8 @@ package event
 // All custom events names must be of this type.
 type Name string
 
-// All custom event types must satisfy this interface.
+// Custom event types may satisfy this interface to handle themselves.
+// The dispatcher never calls Handle on its own, see SubscribeHandle.
 type Event interface {
 	Handle()
 }
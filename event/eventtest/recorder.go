@@ -0,0 +1,179 @@
+package eventtest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/AeroAgency/golang-helpers-lib/event"
+)
+
+// TestingT часть интерфейса *testing.T, используемая проверками
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// Matcher проверяет отправленное событие
+type Matcher func(event interface{}) bool
+
+// Match возвращает Matcher для событий типа T
+func Match[T any](matcher func(event T) bool) Matcher {
+	return func(e interface{}) bool {
+		typed, ok := e.(T)
+		return ok && matcher(typed)
+	}
+}
+
+// Recorder реализация event.DispatcherInterface для тестов:
+// синхронно запоминает отправленные события, не вызывая слушателей
+type Recorder struct {
+	mu      sync.Mutex
+	events  []event.Envelope
+	err     error
+	changed chan struct{}
+}
+
+var _ event.DispatcherInterface = (*Recorder)(nil)
+
+// NewRecorder конструктор
+func NewRecorder() *Recorder {
+	return &Recorder{changed: make(chan struct{})}
+}
+
+// SetError задает ошибку, которую будут возвращать методы отправки
+func (r *Recorder) SetError(err error) *Recorder {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.err = err
+	return r
+}
+
+func (r *Recorder) Dispatch(name event.Name, e interface{}) error {
+	return r.record(name, e)
+}
+
+func (r *Recorder) DispatchContext(_ context.Context, name event.Name, e interface{}) error {
+	return r.record(name, e)
+}
+
+func (r *Recorder) DispatchSync(_ context.Context, name event.Name, e interface{}) error {
+	return r.record(name, e)
+}
+
+// Events возвращает все отправленные события в порядке отправки
+func (r *Recorder) Events() []event.Envelope {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]event.Envelope(nil), r.events...)
+}
+
+// Dispatched возвращает события с указанным именем в порядке отправки
+func (r *Recorder) Dispatched(name event.Name) []interface{} {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.find(name, nil)
+}
+
+// Reset очищает отправленные события
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = nil
+}
+
+// AssertDispatched проверяет, что событие с указанным именем, удовлетворяющее matcher, было отправлено.
+// При пустом matcher подходит любое событие с указанным именем
+func (r *Recorder) AssertDispatched(t TestingT, name event.Name, matcher Matcher) bool {
+	t.Helper()
+
+	r.mu.Lock()
+	found := r.find(name, matcher)
+	r.mu.Unlock()
+
+	if len(found) == 0 {
+		t.Errorf("expected the '%s' event to be dispatched, dispatched events: %s", name, r.names())
+		return false
+	}
+
+	return true
+}
+
+// AssertNotDispatched проверяет, что событие с указанным именем не отправлялось
+func (r *Recorder) AssertNotDispatched(t TestingT, name event.Name) bool {
+	t.Helper()
+
+	if found := r.Dispatched(name); len(found) > 0 {
+		t.Errorf("expected the '%s' event not to be dispatched, dispatched %d times", name, len(found))
+		return false
+	}
+
+	return true
+}
+
+// WaitFor ожидает отправки события с указанным именем, в том числе уже отправленного,
+// и возвращает первое такое событие. Используется, когда код отправляет события из другой горутины
+func (r *Recorder) WaitFor(name event.Name, timeout time.Duration) (interface{}, error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		r.mu.Lock()
+		found := r.find(name, nil)
+		changed := r.changed
+		r.mu.Unlock()
+
+		if len(found) > 0 {
+			return found[0], nil
+		}
+
+		select {
+		case <-changed:
+		case <-timer.C:
+			return nil, fmt.Errorf("the '%s' event was not dispatched within %s", name, timeout)
+		}
+	}
+}
+
+func (r *Recorder) record(name event.Name, e interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.err != nil {
+		return r.err
+	}
+	r.events = append(r.events, event.Envelope{Name: name, Event: e})
+	close(r.changed)
+	r.changed = make(chan struct{})
+
+	return nil
+}
+
+// find возвращает события по имени, вызывается под блокировкой
+func (r *Recorder) find(name event.Name, matcher Matcher) []interface{} {
+	var found []interface{}
+	for _, envelope := range r.events {
+		if envelope.Name == name && (matcher == nil || matcher(envelope.Event)) {
+			found = append(found, envelope.Event)
+		}
+	}
+
+	return found
+}
+
+func (r *Recorder) names() []event.Name {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make([]event.Name, 0, len(r.events))
+	for _, envelope := range r.events {
+		names = append(names, envelope.Name)
+	}
+
+	return names
+}
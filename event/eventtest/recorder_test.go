@@ -0,0 +1,69 @@
+package eventtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AeroAgency/golang-helpers-lib/event"
+	"github.com/stretchr/testify/assert"
+)
+
+type orderCreated struct {
+	ID int
+}
+
+type testingTMock struct {
+	errors []string
+}
+
+func (m *testingTMock) Helper() {}
+
+func (m *testingTMock) Errorf(format string, args ...interface{}) {
+	m.errors = append(m.errors, fmt.Sprintf(format, args...))
+}
+
+func TestRecorder_AssertDispatched(t *testing.T) {
+	r := NewRecorder()
+	assert.NoError(t, event.PublishSync(r, context.Background(), orderCreated{ID: 1}))
+	assert.NoError(t, r.Dispatch("order.deleted", 2))
+
+	name := event.NameOf[orderCreated]()
+	assert.True(t, r.AssertDispatched(t, name, nil))
+	assert.True(t, r.AssertDispatched(t, name, Match(func(e orderCreated) bool { return e.ID == 1 })))
+	assert.True(t, r.AssertNotDispatched(t, "order.updated"))
+	assert.Len(t, r.Events(), 2)
+
+	mock := &testingTMock{}
+	assert.False(t, r.AssertDispatched(mock, name, Match(func(e orderCreated) bool { return e.ID == 2 })))
+	assert.False(t, r.AssertNotDispatched(mock, "order.deleted"))
+	assert.Len(t, mock.errors, 2)
+
+	r.Reset()
+	assert.Empty(t, r.Events())
+}
+
+func TestRecorder_WaitFor(t *testing.T) {
+	r := NewRecorder()
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		_ = r.DispatchContext(context.Background(), "order.created", 1)
+	}()
+
+	e, err := r.WaitFor("order.created", time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, e)
+
+	_, err = r.WaitFor("order.deleted", 10*time.Millisecond)
+	assert.Error(t, err)
+}
+
+func TestRecorder_SetError(t *testing.T) {
+	dispatchErr := errors.New("dispatch failed")
+	r := NewRecorder().SetError(dispatchErr)
+
+	assert.ErrorIs(t, r.Dispatch("order.created", 1), dispatchErr)
+	assert.Empty(t, r.Events())
+}
@@ -1,8 +1,27 @@
 package event
 
+import (
+	"context"
+	"time"
+)
+
 // job represents events. When a new event is dispatched, it
-// gets tuned into a job and put into `Dispatcher.jobs` channel.
+// gets tuned into a job and put into `Dispatcher.queue`.
 type job struct {
+	ctx       context.Context
 	eventName Name
 	eventType interface{}
+	listeners []ContextListener
+	recordID  string
+}
+
+// detachedContext keeps the values of the parent context but is never
+// cancelled. Store writes made after asynchronous delivery use it, because
+// the context of the dispatching request is usually cancelled by then.
+type detachedContext struct {
+	context.Context
 }
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
@@ -1,6 +1,24 @@
 package event
 
+import "context"
+
 // All custom event listeners must satisfy this interface.
 type Listener interface {
 	Listen(event interface{})
 }
+
+// ContextListener receives the context the event was dispatched with
+// and reports the handling error back to the dispatcher.
+type ContextListener interface {
+	ListenContext(ctx context.Context, event interface{}) error
+}
+
+// listenerAdapter allows plain listeners to be used where ContextListener is expected.
+type listenerAdapter struct {
+	listener Listener
+}
+
+func (a listenerAdapter) ListenContext(_ context.Context, event interface{}) error {
+	a.listener.Listen(event)
+	return nil
+}
@@ -0,0 +1,19 @@
+package event
+
+import (
+	"context"
+	"time"
+)
+
+// Observer получает уведомления о работе диспетчера: используется для метрик и трассировки.
+// Методы вызываются синхронно и не должны блокироваться
+type Observer interface {
+	// Dispatched вызывается после приема события диспетчером
+	Dispatched(ctx context.Context, name Name)
+	// QueueDepth вызывается при изменении количества событий в очереди
+	QueueDepth(depth int)
+	// ListenerStarted вызывается перед вызовом слушателя, возвращенный контекст передается слушателю
+	ListenerStarted(ctx context.Context, name Name) context.Context
+	// ListenerFinished вызывается после завершения слушателя с контекстом, возвращенным ListenerStarted
+	ListenerFinished(ctx context.Context, name Name, duration time.Duration, err error)
+}
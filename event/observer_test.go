@@ -0,0 +1,62 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type observerMock struct {
+	mu         sync.Mutex
+	dispatched []Name
+	depths     []int
+	finished   []error
+}
+
+func (o *observerMock) Dispatched(_ context.Context, name Name) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.dispatched = append(o.dispatched, name)
+}
+
+func (o *observerMock) QueueDepth(depth int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.depths = append(o.depths, depth)
+}
+
+func (o *observerMock) ListenerStarted(ctx context.Context, name Name) context.Context {
+	return context.WithValue(ctx, ctxKey{}, name)
+}
+
+func (o *observerMock) ListenerFinished(ctx context.Context, name Name, _ time.Duration, err error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if ctx.Value(ctxKey{}) == name {
+		o.finished = append(o.finished, err)
+	}
+}
+
+func TestDispatcher_Observer(t *testing.T) {
+	observer := &observerMock{}
+	d := NewDispatcher().AddObserver(observer)
+	listenErr := errors.New("listener failed")
+	var listenerCtx context.Context
+	assert.NoError(t, d.RegisterContext(ListenerFunc(func(ctx context.Context, event interface{}) error {
+		listenerCtx = ctx
+		return listenErr
+	}), "order.created"))
+
+	assert.Error(t, d.DispatchSync(context.Background(), "order.created", struct{}{}))
+	assert.NoError(t, d.Dispatch("order.created", struct{}{}))
+	assert.NoError(t, d.Close(context.Background()))
+
+	assert.Equal(t, Name("order.created"), listenerCtx.Value(ctxKey{}))
+	assert.Equal(t, []Name{"order.created", "order.created"}, observer.dispatched)
+	assert.Equal(t, []error{listenErr, listenErr}, observer.finished)
+	assert.ElementsMatch(t, []int{1, 0}, observer.depths)
+}
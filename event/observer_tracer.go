@@ -0,0 +1,44 @@
+package event
+
+import (
+	"context"
+	"time"
+
+	"github.com/AeroAgency/golang-helpers-lib/tracing"
+)
+
+// TracerObserver создает span на каждый вызов слушателя как дочерний к спану из контекста.
+// Трейсер слушателя доступен через tracing.FromContext
+type TracerObserver struct {
+	provider tracing.ProviderInterface
+}
+
+// NewTracerObserver конструктор. При пустом provider используется tracing.OtelProvider
+// с глобальным TracerProvider
+func NewTracerObserver(provider tracing.ProviderInterface) *TracerObserver {
+	if provider == nil {
+		provider = tracing.OtelProvider{}
+	}
+	return &TracerObserver{provider: provider}
+}
+
+func (o TracerObserver) Dispatched(context.Context, Name) {}
+
+func (o TracerObserver) QueueDepth(int) {}
+
+func (o TracerObserver) ListenerStarted(ctx context.Context, name Name) context.Context {
+	ctx, tracer := o.provider.Start(ctx, "event "+string(name))
+	tracer.SetTag("event.name", string(name))
+
+	return ctx
+}
+
+func (o TracerObserver) ListenerFinished(ctx context.Context, _ Name, duration time.Duration, err error) {
+	tracer := tracing.FromContext(ctx)
+	defer tracer.Close()
+
+	tracer.SetTag("event.duration", duration.String())
+	if err != nil {
+		tracer.LogError(err)
+	}
+}
@@ -0,0 +1,40 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/AeroAgency/golang-helpers-lib/tracing"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracerObserver(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tracer := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)).Tracer("test")
+	d := NewDispatcher().AddObserver(NewTracerObserver(tracing.OtelProvider{Tracer: tracer}))
+
+	var listenerSpan trace.SpanContext
+	assert.NoError(t, d.RegisterContext(ListenerFunc(func(ctx context.Context, event interface{}) error {
+		listenerSpan = trace.SpanContextFromContext(ctx)
+		return errors.New("listener failed")
+	}), "order.created"))
+
+	ctx, parent := tracer.Start(context.Background(), "request")
+	assert.Error(t, d.DispatchSync(ctx, "order.created", struct{}{}))
+	parent.End()
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 2)
+	span := spans[0]
+	assert.Equal(t, "event order.created", span.Name)
+	assert.Equal(t, parent.SpanContext().SpanID(), span.Parent.SpanID())
+	assert.Equal(t, span.SpanContext.SpanID(), listenerSpan.SpanID())
+	assert.Contains(t, span.Attributes, attribute.String("event.name", "order.created"))
+	assert.Equal(t, codes.Error, span.Status.Code)
+}
@@ -0,0 +1,76 @@
+package event
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DefaultScheduleTable таблица запланированных событий по умолчанию для GormScheduleStore
+const DefaultScheduleTable = "scheduled_events"
+
+// GormScheduleStore хранилище запланированных событий в таблице базы данных
+type GormScheduleStore struct {
+	db    *gorm.DB
+	table string
+}
+
+type gormScheduledEvent struct {
+	ID      string    `gorm:"primaryKey;size:36"`
+	Name    string    `gorm:"size:255"`
+	Payload []byte    `gorm:"not null"`
+	RunAt   time.Time `gorm:"index"`
+	Cron    string    `gorm:"size:255"`
+}
+
+// NewGormScheduleStore конструктор. При пустом имени таблицы используется DefaultScheduleTable
+func NewGormScheduleStore(db *gorm.DB, table string) *GormScheduleStore {
+	if table == "" {
+		table = DefaultScheduleTable
+	}
+
+	return &GormScheduleStore{db: db, table: table}
+}
+
+// Migrate создает или обновляет таблицу запланированных событий
+func (s *GormScheduleStore) Migrate(ctx context.Context) error {
+	return s.db.WithContext(ctx).Table(s.table).AutoMigrate(&gormScheduledEvent{})
+}
+
+// Save создает или обновляет запланированное событие
+func (s *GormScheduleStore) Save(ctx context.Context, event ScheduledEvent) error {
+	return s.db.WithContext(ctx).Table(s.table).Save(&gormScheduledEvent{
+		ID:      event.ID,
+		Name:    string(event.Name),
+		Payload: event.Payload,
+		RunAt:   event.RunAt,
+		Cron:    event.Cron,
+	}).Error
+}
+
+// Delete удаляет запланированное событие
+func (s *GormScheduleStore) Delete(ctx context.Context, id string) error {
+	return s.db.WithContext(ctx).Table(s.table).Where("id = ?", id).Delete(&gormScheduledEvent{}).Error
+}
+
+// List возвращает запланированные события в порядке времени отправки
+func (s *GormScheduleStore) List(ctx context.Context) ([]ScheduledEvent, error) {
+	var rows []gormScheduledEvent
+	if err := s.db.WithContext(ctx).Table(s.table).Order("run_at").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	events := make([]ScheduledEvent, 0, len(rows))
+	for _, row := range rows {
+		events = append(events, ScheduledEvent{
+			ID:      row.ID,
+			Name:    Name(row.Name),
+			Payload: row.Payload,
+			RunAt:   row.RunAt,
+			Cron:    row.Cron,
+		})
+	}
+
+	return events, nil
+}
@@ -0,0 +1,293 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// ErrScheduleNotFound возвращается при отмене неизвестного запланированного события
+var ErrScheduleNotFound = errors.New("the scheduled event is not found")
+
+// ErrSchedulerStopped возвращается при планировании и восстановлении событий после Stop
+var ErrSchedulerStopped = errors.New("the scheduler is stopped")
+
+// ScheduledEvent отложенное или периодическое событие
+type ScheduledEvent struct {
+	ID      string
+	Name    Name
+	Payload json.RawMessage
+	RunAt   time.Time // время ближайшей отправки
+	Cron    string    // cron-выражение периодического события, пусто для однократного
+}
+
+// ScheduleStore хранилище запланированных событий, позволяющее восстановить расписание после перезапуска
+type ScheduleStore interface {
+	// Save создает или обновляет запланированное событие
+	Save(ctx context.Context, event ScheduledEvent) error
+	// Delete удаляет запланированное событие
+	Delete(ctx context.Context, id string) error
+	// List возвращает все запланированные события
+	List(ctx context.Context) ([]ScheduledEvent, error)
+}
+
+// Scheduler отправляет события через диспетчер в заданное время или по cron-расписанию.
+// Ошибки отправки передаются обработчику ошибок диспетчера
+type Scheduler struct {
+	mu         sync.Mutex
+	dispatcher *Dispatcher
+	store      ScheduleStore
+	entries    map[string]*scheduledEntry
+	stopped    bool
+}
+
+type scheduledEntry struct {
+	event     ScheduledEvent
+	payload   interface{}
+	schedule  *cronSchedule
+	timer     *time.Timer
+	cancelled bool
+}
+
+// NewScheduler конструктор
+func NewScheduler(dispatcher *Dispatcher) *Scheduler {
+	return &Scheduler{
+		dispatcher: dispatcher,
+		entries:    make(map[string]*scheduledEntry),
+	}
+}
+
+// SetStore установка хранилища запланированных событий
+func (s *Scheduler) SetStore(store ScheduleStore) *Scheduler {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.store = store
+	return s
+}
+
+// DispatchAt планирует отправку события в указанное время и возвращает идентификатор для отмены
+func (s *Scheduler) DispatchAt(ctx context.Context, at time.Time, name Name, event interface{}) (string, error) {
+	return s.schedule(ctx, ScheduledEvent{Name: name, RunAt: at}, event, nil)
+}
+
+// DispatchAfter планирует отправку события через указанный промежуток времени
+func (s *Scheduler) DispatchAfter(ctx context.Context, delay time.Duration, name Name, event interface{}) (string, error) {
+	return s.DispatchAt(ctx, time.Now().Add(delay), name, event)
+}
+
+// DispatchCron планирует периодическую отправку события по cron-выражению
+// из пяти полей (минута, час, день месяца, месяц, день недели) или сокращению "@daily"
+func (s *Scheduler) DispatchCron(ctx context.Context, spec string, name Name, event interface{}) (string, error) {
+	schedule, err := parseCron(spec)
+	if err != nil {
+		return "", err
+	}
+
+	runAt := schedule.next(time.Now())
+	if runAt.IsZero() {
+		return "", fmt.Errorf("cron expression '%s' never fires", spec)
+	}
+
+	return s.schedule(ctx, ScheduledEvent{Name: name, RunAt: runAt, Cron: spec}, event, schedule)
+}
+
+// Cancel отменяет запланированное событие
+func (s *Scheduler) Cancel(ctx context.Context, id string) error {
+	s.mu.Lock()
+	entry, ok := s.entries[id]
+	if ok {
+		entry.timer.Stop()
+		entry.cancelled = true
+		delete(s.entries, id)
+	}
+	store := s.store
+	s.mu.Unlock()
+
+	if !ok {
+		return ErrScheduleNotFound
+	}
+	if store != nil {
+		return store.Delete(ctx, id)
+	}
+
+	return nil
+}
+
+// Restore восстанавливает расписание из хранилища. Просроченные события отправляются сразу.
+// Вызывается после подписки слушателей и регистрации типов событий в диспетчере.
+// После Stop возвращает ErrSchedulerStopped
+func (s *Scheduler) Restore(ctx context.Context) error {
+	s.mu.Lock()
+	store, stopped := s.store, s.stopped
+	s.mu.Unlock()
+	if stopped {
+		return ErrSchedulerStopped
+	}
+	if store == nil {
+		return errors.New("the schedule store is not set")
+	}
+
+	events, err := store.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		var schedule *cronSchedule
+		if event.Cron != "" {
+			if schedule, err = parseCron(event.Cron); err != nil {
+				return err
+			}
+		}
+
+		payload, err := s.dispatcher.decode(Record{Name: event.Name, Payload: event.Payload})
+		if err != nil {
+			return fmt.Errorf("the '%s' scheduled event %s: %w", event.Name, event.ID, err)
+		}
+
+		s.mu.Lock()
+		if s.stopped {
+			s.mu.Unlock()
+			return ErrSchedulerStopped
+		}
+		if _, ok := s.entries[event.ID]; !ok {
+			s.start(&scheduledEntry{event: event, payload: payload, schedule: schedule})
+		}
+		s.mu.Unlock()
+	}
+
+	return nil
+}
+
+// Stop отменяет таймеры запланированных событий, после него планировщик не используется.
+// События остаются в хранилище и восстанавливаются Restore нового планировщика
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stopped = true
+	for _, entry := range s.entries {
+		entry.timer.Stop()
+	}
+	s.entries = make(map[string]*scheduledEntry)
+}
+
+func (s *Scheduler) schedule(ctx context.Context, event ScheduledEvent, payload interface{}, schedule *cronSchedule) (string, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", fmt.Errorf("the '%s' event can not be scheduled: %w", event.Name, err)
+	}
+	event.ID = uuid.NewV4().String()
+	event.Payload = data
+
+	s.mu.Lock()
+	store, stopped := s.store, s.stopped
+	s.mu.Unlock()
+	if stopped {
+		return "", ErrSchedulerStopped
+	}
+	if store != nil {
+		if err := store.Save(ctx, event); err != nil {
+			return "", err
+		}
+	}
+
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		if store != nil {
+			if err := store.Delete(ctx, event.ID); err != nil {
+				return "", err
+			}
+		}
+		return "", ErrSchedulerStopped
+	}
+	s.start(&scheduledEntry{event: event, payload: payload, schedule: schedule})
+	s.mu.Unlock()
+
+	return event.ID, nil
+}
+
+// start запускает таймер события, вызывается под блокировкой
+func (s *Scheduler) start(entry *scheduledEntry) {
+	s.entries[entry.event.ID] = entry
+	entry.timer = time.AfterFunc(time.Until(entry.event.RunAt), func() {
+		s.fire(entry)
+	})
+}
+
+// fire отправляет событие и планирует следующую отправку периодического события.
+// Событие без слушателей не отправляется, ErrNotRegistered передается обработчику ошибок.
+// Однократное событие удаляется из хранилища только после постановки в очередь диспетчера,
+// неотправленное событие остается в хранилище и отправляется после Restore
+func (s *Scheduler) fire(entry *scheduledEntry) {
+	ctx := context.Background()
+
+	s.mu.Lock()
+	if s.entries[entry.event.ID] != entry {
+		s.mu.Unlock()
+		return
+	}
+	event := entry.event
+	done := entry.schedule == nil
+	if !done {
+		next := entry.schedule.next(time.Now())
+		if done = next.IsZero(); !done {
+			entry.event.RunAt = next
+			s.start(entry)
+		}
+	}
+	if done {
+		delete(s.entries, event.ID)
+	}
+	store := s.store
+	s.mu.Unlock()
+
+	dispatchErr := s.dispatcher.enqueue(ctx, event.Name, entry.payload, ErrorOnUnregistered)
+	if dispatchErr != nil {
+		s.dispatcher.handleError(ctx, event.Name, dispatchErr)
+	}
+	if store == nil {
+		return
+	}
+
+	var err error
+	if !done {
+		err = s.saveNext(ctx, store, entry)
+	} else if dispatchErr == nil {
+		err = store.Delete(ctx, event.ID)
+	}
+	if err != nil {
+		s.dispatcher.handleError(ctx, event.Name, err)
+	}
+}
+
+// saveNext сохраняет время следующей отправки периодического события.
+// Запись выполняется без блокировки, событие, отмененное во время записи, удаляется повторно
+func (s *Scheduler) saveNext(ctx context.Context, store ScheduleStore, entry *scheduledEntry) error {
+	s.mu.Lock()
+	event, cancelled := entry.event, entry.cancelled
+	s.mu.Unlock()
+	if cancelled {
+		return nil
+	}
+
+	if err := store.Save(ctx, event); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	cancelled = entry.cancelled
+	s.mu.Unlock()
+	if cancelled {
+		return store.Delete(ctx, event.ID)
+	}
+
+	return nil
+}
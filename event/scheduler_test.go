@@ -0,0 +1,203 @@
+package event
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type scheduleStoreMock struct {
+	mu     sync.Mutex
+	events map[string]ScheduledEvent
+	onSave func(event ScheduledEvent)
+}
+
+func (s *scheduleStoreMock) Save(_ context.Context, event ScheduledEvent) error {
+	if s.onSave != nil {
+		s.onSave(event)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events[event.ID] = event
+	return nil
+}
+
+func (s *scheduleStoreMock) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.events, id)
+	return nil
+}
+
+func (s *scheduleStoreMock) len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.events)
+}
+
+func (s *scheduleStoreMock) List(_ context.Context) ([]ScheduledEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var events []ScheduledEvent
+	for _, event := range s.events {
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func TestCron_Next(t *testing.T) {
+	from := time.Date(2024, time.January, 31, 10, 7, 30, 0, time.UTC)
+	cases := []struct {
+		spec string
+		next time.Time
+	}{
+		{"*/15 * * * *", time.Date(2024, time.January, 31, 10, 15, 0, 0, time.UTC)},
+		{"0 9-18 * * *", time.Date(2024, time.January, 31, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{"30 8 * * 1-5", time.Date(2024, time.February, 1, 8, 30, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 12 * * 7", time.Date(2024, time.February, 4, 12, 0, 0, 0, time.UTC)},
+		{"0 0 15 * 3", time.Date(2024, time.February, 7, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, c := range cases {
+		schedule, err := parseCron(c.spec)
+		assert.NoError(t, err, c.spec)
+		assert.Equal(t, c.next, schedule.next(from), c.spec)
+	}
+
+	for _, spec := range []string{"* * * *", "60 * * * *", "*/0 * * * *", "5-1 * * * *", "a * * * *"} {
+		_, err := parseCron(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestScheduler_DispatchAfter(t *testing.T) {
+	calls := make(chan string, 10)
+	d := NewDispatcher()
+	assert.NoError(t, d.Register(listenerMock{id: "reminder", calls: calls}, "order.reminder"))
+	store := &scheduleStoreMock{events: make(map[string]ScheduledEvent)}
+	s := NewScheduler(d).SetStore(store)
+
+	ctx := context.Background()
+	_, err := s.DispatchAfter(ctx, 10*time.Millisecond, "order.reminder", struct{}{})
+	assert.NoError(t, err)
+	canceled, err := s.DispatchAfter(ctx, 10*time.Millisecond, "order.reminder", struct{}{})
+	assert.NoError(t, err)
+	assert.NoError(t, s.Cancel(ctx, canceled))
+	assert.ErrorIs(t, s.Cancel(ctx, canceled), ErrScheduleNotFound)
+
+	assert.Equal(t, []string{"reminder"}, receive(t, calls, 1))
+	assert.NoError(t, d.Close(ctx))
+	assert.Empty(t, calls)
+	assert.Eventually(t, func() bool { return store.len() == 0 }, time.Second, time.Millisecond)
+}
+
+func TestScheduler_DispatchWithoutListeners(t *testing.T) {
+	errs := make(chan error, 1)
+	d := NewDispatcher().SetErrorHandler(func(_ context.Context, _ Name, err error) {
+		errs <- err
+	})
+	store := &scheduleStoreMock{events: make(map[string]ScheduledEvent)}
+	s := NewScheduler(d).SetStore(store)
+
+	_, err := s.DispatchAfter(context.Background(), 0, "nobody", 1)
+	assert.NoError(t, err)
+
+	select {
+	case err := <-errs:
+		assert.ErrorIs(t, err, ErrNotRegistered)
+	case <-time.After(time.Second):
+		t.Fatal("the error handler was not called")
+	}
+	assert.Equal(t, 1, store.len())
+}
+
+func TestScheduler_Restore(t *testing.T) {
+	store := &scheduleStoreMock{events: make(map[string]ScheduledEvent)}
+	ctx := context.Background()
+
+	stopped := NewScheduler(NewDispatcher()).SetStore(store)
+	_, err := stopped.DispatchAt(ctx, time.Now().Add(-time.Minute), "payment.received", paymentReceived{Amount: 10})
+	assert.NoError(t, err)
+	stopped.Stop()
+	_, err = stopped.DispatchCron(ctx, "@hourly", "payment.received", paymentReceived{Amount: 20})
+	assert.ErrorIs(t, err, ErrSchedulerStopped)
+	assert.ErrorIs(t, stopped.Restore(ctx), ErrSchedulerStopped)
+
+	got := make(chan paymentReceived, 1)
+	d := NewDispatcher()
+	d.RegisterType("payment.received", paymentReceived{})
+	assert.NoError(t, d.RegisterContext(ListenerFunc(func(ctx context.Context, event interface{}) error {
+		got <- event.(paymentReceived)
+		return nil
+	}), "payment.received"))
+
+	assert.NoError(t, NewScheduler(d).SetStore(store).Restore(ctx))
+	select {
+	case event := <-got:
+		assert.Equal(t, paymentReceived{Amount: 10}, event)
+	case <-time.After(time.Second):
+		t.Fatal("scheduled event was not restored")
+	}
+}
+
+func TestScheduler_StoreOutsideLock(t *testing.T) {
+	saving := make(chan struct{})
+	release := make(chan struct{})
+	store := &scheduleStoreMock{events: make(map[string]ScheduledEvent), onSave: func(ScheduledEvent) {
+		close(saving)
+		<-release
+	}}
+	s := NewScheduler(NewDispatcher()).SetStore(store)
+	defer s.Stop()
+
+	scheduled := make(chan error, 1)
+	go func() {
+		_, err := s.DispatchAfter(context.Background(), time.Hour, "order.expired", 1)
+		scheduled <- err
+	}()
+	<-saving
+
+	// Медленная запись в хранилище не блокирует другие операции планировщика
+	cancelled := make(chan error, 1)
+	go func() {
+		cancelled <- s.Cancel(context.Background(), "unknown")
+	}()
+	select {
+	case err := <-cancelled:
+		assert.ErrorIs(t, err, ErrScheduleNotFound)
+	case <-time.After(time.Second):
+		t.Fatal("Cancel is blocked by the store")
+	}
+
+	close(release)
+	assert.NoError(t, <-scheduled)
+	assert.Equal(t, 1, store.len())
+}
+
+func TestScheduler_CancelDuringSave(t *testing.T) {
+	store := &scheduleStoreMock{events: make(map[string]ScheduledEvent)}
+	s := NewScheduler(NewDispatcher()).SetStore(store)
+	ctx := context.Background()
+
+	entry := &scheduledEntry{event: ScheduledEvent{ID: "1", Name: "report.daily", RunAt: time.Now().Add(time.Hour), Cron: "@daily"}}
+	s.mu.Lock()
+	s.start(entry)
+	s.mu.Unlock()
+
+	// Отмена между чтением события и записью в хранилище
+	store.onSave = func(ScheduledEvent) {
+		assert.NoError(t, s.Cancel(ctx, "1"))
+	}
+	assert.NoError(t, s.saveNext(ctx, store, entry))
+	assert.Equal(t, 0, store.len())
+
+	// Отмененное событие больше не сохраняется
+	store.onSave = nil
+	assert.NoError(t, s.saveNext(ctx, store, entry))
+	assert.Equal(t, 0, store.len())
+}
@@ -0,0 +1,60 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Record событие, сохраненное в хранилище до доставки слушателям
+type Record struct {
+	ID          string
+	Name        Name
+	Payload     json.RawMessage
+	CreatedAt   time.Time
+	DeliveredAt *time.Time
+}
+
+// Filter условия выборки сохраненных событий. Пустые поля не ограничивают выборку
+type Filter struct {
+	Names       []Name    // имена событий
+	From        time.Time // события, сохраненные не раньше указанного времени
+	To          time.Time // события, сохраненные раньше указанного времени
+	Undelivered bool      // только события, не доставленные всем слушателям
+}
+
+// Store хранилище отправленных событий.
+// Доставка событий из хранилища происходит по принципу "хотя бы один раз":
+// при повторе событие получают все его слушатели, в том числе уже успешно завершенные,
+// так как завершение отдельных слушателей не записывается. Слушатели должны быть идемпотентны
+type Store interface {
+	// Save сохраняет событие перед доставкой
+	Save(ctx context.Context, record Record) error
+	// MarkDelivered отмечает успешное завершение всех слушателей события
+	MarkDelivered(ctx context.Context, id string, deliveredAt time.Time) error
+	// Find возвращает события в порядке сохранения
+	Find(ctx context.Context, filter Filter) ([]Record, error)
+}
+
+// matches проверяет соответствие записи фильтру
+func (f Filter) matches(record Record) bool {
+	if f.Undelivered && record.DeliveredAt != nil {
+		return false
+	}
+	if !f.From.IsZero() && record.CreatedAt.Before(f.From) {
+		return false
+	}
+	if !f.To.IsZero() && !record.CreatedAt.Before(f.To) {
+		return false
+	}
+	if len(f.Names) == 0 {
+		return true
+	}
+	for _, name := range f.Names {
+		if name == record.Name {
+			return true
+		}
+	}
+
+	return false
+}
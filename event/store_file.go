@@ -0,0 +1,163 @@
+package event
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	fileStoreOpSave      = "save"
+	fileStoreOpDelivered = "delivered"
+)
+
+// FileStore хранилище событий в локальном файле, открытом только на дозапись.
+// Каждая строка файла - JSON-запись о сохранении или доставке события
+type FileStore struct {
+	mu   sync.Mutex
+	file *os.File
+	path string
+}
+
+type fileStoreEntry struct {
+	Op          string     `json:"op"`
+	Record      *Record    `json:"record,omitempty"`
+	ID          string     `json:"id,omitempty"`
+	DeliveredAt *time.Time `json:"deliveredAt,omitempty"`
+}
+
+// NewFileStore открывает или создает файл хранилища. Незавершенная последняя строка,
+// оставшаяся после сбоя во время записи, отбрасывается
+func NewFileStore(path string) (*FileStore, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if err := truncatePartialLine(file); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("event store %s: %w", path, err)
+	}
+
+	return &FileStore{file: file, path: path}, nil
+}
+
+// truncatePartialLine обрезает файл после последнего перевода строки
+func truncatePartialLine(file *os.File) error {
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	end := info.Size()
+	buf := make([]byte, 4096)
+	for offset := end; offset > 0; {
+		size := int64(len(buf))
+		if offset < size {
+			size = offset
+		}
+		offset -= size
+		if _, err := file.ReadAt(buf[:size], offset); err != nil {
+			return err
+		}
+		if i := bytes.LastIndexByte(buf[:size], '\n'); i >= 0 {
+			if offset+int64(i)+1 == end {
+				return nil
+			}
+			return file.Truncate(offset + int64(i) + 1)
+		}
+	}
+	if end == 0 {
+		return nil
+	}
+	return file.Truncate(0)
+}
+
+// Save дописывает событие в файл
+func (s *FileStore) Save(_ context.Context, record Record) error {
+	return s.append(fileStoreEntry{Op: fileStoreOpSave, Record: &record})
+}
+
+// MarkDelivered дописывает в файл отметку о доставке события
+func (s *FileStore) MarkDelivered(_ context.Context, id string, deliveredAt time.Time) error {
+	return s.append(fileStoreEntry{Op: fileStoreOpDelivered, ID: id, DeliveredAt: &deliveredAt})
+}
+
+// Find читает файл целиком и возвращает события, удовлетворяющие фильтру.
+// Последняя строка, которую не удалось разобрать (запись прервана), пропускается
+func (s *FileStore) Find(_ context.Context, filter Filter) ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.Open(s.path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var records []*Record
+	byID := make(map[string]*Record)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	var parseErr error
+	for line := 1; scanner.Scan(); line++ {
+		if parseErr != nil {
+			return nil, parseErr
+		}
+		var entry fileStoreEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			parseErr = fmt.Errorf("event store %s:%d: %w", s.path, line, err)
+			continue
+		}
+
+		switch entry.Op {
+		case fileStoreOpSave:
+			records = append(records, entry.Record)
+			byID[entry.Record.ID] = entry.Record
+		case fileStoreOpDelivered:
+			if record, ok := byID[entry.ID]; ok {
+				record.DeliveredAt = entry.DeliveredAt
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var found []Record
+	for _, record := range records {
+		if filter.matches(*record) {
+			found = append(found, *record)
+		}
+	}
+
+	return found, nil
+}
+
+// Close закрывает файл хранилища
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.file.Close()
+}
+
+func (s *FileStore) append(entry fileStoreEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+
+	return s.file.Sync()
+}
@@ -0,0 +1,92 @@
+package event
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DefaultStoreTable таблица событий по умолчанию для GormStore
+const DefaultStoreTable = "dispatched_events"
+
+// GormStore хранилище событий в таблице базы данных
+type GormStore struct {
+	db    *gorm.DB
+	table string
+}
+
+type gormRecord struct {
+	ID          string     `gorm:"primaryKey;size:36"`
+	Name        string     `gorm:"size:255;index"`
+	Payload     []byte     `gorm:"not null"`
+	CreatedAt   time.Time  `gorm:"index"`
+	DeliveredAt *time.Time `gorm:"index"`
+}
+
+// NewGormStore конструктор. При пустом имени таблицы используется DefaultStoreTable
+func NewGormStore(db *gorm.DB, table string) *GormStore {
+	if table == "" {
+		table = DefaultStoreTable
+	}
+
+	return &GormStore{db: db, table: table}
+}
+
+// Migrate создает или обновляет таблицу событий
+func (s *GormStore) Migrate(ctx context.Context) error {
+	return s.db.WithContext(ctx).Table(s.table).AutoMigrate(&gormRecord{})
+}
+
+// Save сохраняет событие
+func (s *GormStore) Save(ctx context.Context, record Record) error {
+	return s.db.WithContext(ctx).Table(s.table).Create(&gormRecord{
+		ID:          record.ID,
+		Name:        string(record.Name),
+		Payload:     record.Payload,
+		CreatedAt:   record.CreatedAt,
+		DeliveredAt: record.DeliveredAt,
+	}).Error
+}
+
+// MarkDelivered отмечает доставку события
+func (s *GormStore) MarkDelivered(ctx context.Context, id string, deliveredAt time.Time) error {
+	return s.db.WithContext(ctx).Table(s.table).
+		Where("id = ?", id).
+		Update("delivered_at", deliveredAt).Error
+}
+
+// Find возвращает события, удовлетворяющие фильтру
+func (s *GormStore) Find(ctx context.Context, filter Filter) ([]Record, error) {
+	query := s.db.WithContext(ctx).Table(s.table)
+	if len(filter.Names) > 0 {
+		query = query.Where("name IN ?", filter.Names)
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+	if filter.Undelivered {
+		query = query.Where("delivered_at IS NULL")
+	}
+
+	var rows []gormRecord
+	if err := query.Order("created_at, id").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	records := make([]Record, 0, len(rows))
+	for _, row := range rows {
+		records = append(records, Record{
+			ID:          row.ID,
+			Name:        Name(row.Name),
+			Payload:     row.Payload,
+			CreatedAt:   row.CreatedAt,
+			DeliveredAt: row.DeliveredAt,
+		})
+	}
+
+	return records, nil
+}
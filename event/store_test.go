@@ -0,0 +1,163 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type paymentReceived struct {
+	Amount int
+}
+
+func TestFileStore(t *testing.T) {
+	store, err := NewFileStore(filepath.Join(t.TempDir(), "events.log"))
+	assert.NoError(t, err)
+	defer store.Close()
+
+	ctx := context.Background()
+	now := time.Now().UTC()
+	assert.NoError(t, store.Save(ctx, Record{ID: "1", Name: "order.created", Payload: []byte(`{}`), CreatedAt: now}))
+	assert.NoError(t, store.Save(ctx, Record{ID: "2", Name: "order.deleted", Payload: []byte(`{}`), CreatedAt: now.Add(time.Minute)}))
+	assert.NoError(t, store.MarkDelivered(ctx, "1", now))
+
+	records, err := store.Find(ctx, Filter{})
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.NotNil(t, records[0].DeliveredAt)
+
+	records, err = store.Find(ctx, Filter{Undelivered: true})
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, "2", records[0].ID)
+
+	records, err = store.Find(ctx, Filter{Names: []Name{"order.created"}, To: now.Add(time.Second)})
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, "1", records[0].ID)
+}
+
+func TestFileStore_PartialLastLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.log")
+	store, err := NewFileStore(path)
+	assert.NoError(t, err)
+	ctx := context.Background()
+	assert.NoError(t, store.Save(ctx, Record{ID: "1", Name: "order.created", Payload: []byte(`{}`)}))
+	assert.NoError(t, store.Close())
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	assert.NoError(t, err)
+	_, err = file.WriteString(`{"op":"save","record":{"ID":"2","Na`)
+	assert.NoError(t, err)
+	assert.NoError(t, file.Close())
+
+	// Find пропускает незавершенную последнюю строку
+	records, err := (&FileStore{path: path}).Find(ctx, Filter{})
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+
+	// NewFileStore отбрасывает ее, следующая запись не повреждается
+	store, err = NewFileStore(path)
+	assert.NoError(t, err)
+	defer store.Close()
+	assert.NoError(t, store.Save(ctx, Record{ID: "3", Name: "order.created", Payload: []byte(`{}`)}))
+
+	records, err = store.Find(ctx, Filter{})
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, "3", records[1].ID)
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(data), "\n"))
+}
+
+func TestFileStore_CorruptedLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.log")
+	assert.NoError(t, os.WriteFile(path, []byte("broken\n{\"op\":\"save\",\"record\":{\"ID\":\"1\"}}\n"), 0o644))
+
+	_, err := (&FileStore{path: path}).Find(context.Background(), Filter{})
+	assert.Error(t, err)
+}
+
+func TestDispatcher_ReplayPending(t *testing.T) {
+	store, err := NewFileStore(filepath.Join(t.TempDir(), "events.log"))
+	assert.NoError(t, err)
+	defer store.Close()
+	ctx := context.Background()
+
+	failing := NewDispatcher().SetStore(store)
+	_, err = Subscribe(failing, func(ctx context.Context, event paymentReceived) error {
+		return errors.New("listener bug")
+	})
+	assert.NoError(t, err)
+	assert.Error(t, PublishSync(failing, ctx, paymentReceived{Amount: 100}))
+
+	var got []paymentReceived
+	restarted := NewDispatcher().SetStore(store)
+	_, err = Subscribe(restarted, func(ctx context.Context, event paymentReceived) error {
+		got = append(got, event)
+		return nil
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, restarted.ReplayPending(ctx))
+	assert.Equal(t, []paymentReceived{{Amount: 100}}, got)
+
+	assert.NoError(t, restarted.ReplayPending(ctx))
+	assert.Len(t, got, 1)
+
+	assert.NoError(t, restarted.Replay(ctx, Filter{Names: []Name{NameOf[paymentReceived]()}}))
+	assert.Len(t, got, 2)
+}
+
+// contextStore отклоняет запись с отмененным контекстом, как GormStore
+type contextStore struct {
+	*FileStore
+}
+
+func (s contextStore) MarkDelivered(ctx context.Context, id string, at time.Time) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return s.FileStore.MarkDelivered(ctx, id, at)
+}
+
+func TestDispatcher_MarkDeliveredAfterCancel(t *testing.T) {
+	fileStore, err := NewFileStore(filepath.Join(t.TempDir(), "events.log"))
+	assert.NoError(t, err)
+	defer fileStore.Close()
+
+	errs := make(chan error, 1)
+	release := make(chan struct{})
+	d := NewDispatcher().SetStore(contextStore{fileStore}).SetErrorHandler(func(_ context.Context, _ Name, err error) {
+		errs <- err
+	})
+	_, err = Subscribe(d, func(ctx context.Context, event paymentReceived) error {
+		<-release
+		return nil
+	})
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	assert.NoError(t, Publish(d, ctx, paymentReceived{Amount: 100}))
+	cancel()
+	close(release)
+	assert.NoError(t, d.Close(context.Background()))
+	assert.Empty(t, errs)
+
+	records, err := fileStore.Find(context.Background(), Filter{Undelivered: true})
+	assert.NoError(t, err)
+	assert.Empty(t, records)
+
+	assert.ErrorIs(t, Publish(d, context.Background(), paymentReceived{Amount: 200}), ErrClosed)
+	records, err = fileStore.Find(context.Background(), Filter{})
+	assert.NoError(t, err)
+	assert.Len(t, records, 1)
+}
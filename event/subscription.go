@@ -0,0 +1,58 @@
+package event
+
+import "strings"
+
+// DefaultPriority приоритет слушателей, зарегистрированных через Dispatcher.Register
+const DefaultPriority = 0
+
+// Subscription подписка слушателя на событие
+type Subscription struct {
+	id         uint64
+	dispatcher *Dispatcher
+}
+
+// Unsubscribe отменяет подписку. Повторный вызов ничего не делает
+func (s *Subscription) Unsubscribe() {
+	s.dispatcher.unsubscribe(s.id)
+}
+
+type subscription struct {
+	id       uint64
+	pattern  Name
+	priority int
+	listener ContextListener
+}
+
+// match проверяет соответствие имени события шаблону подписки
+func match(pattern, name Name) bool {
+	if !strings.Contains(string(pattern), "*") {
+		return pattern == name
+	}
+
+	return matchSegments(strings.Split(string(pattern), "."), strings.Split(string(name), "."))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case "**":
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(pattern[1:], name[i:]) {
+					return true
+				}
+			}
+			return false
+		case "*":
+			if len(name) == 0 {
+				return false
+			}
+		default:
+			if len(name) == 0 || pattern[0] != name[0] {
+				return false
+			}
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+
+	return len(name) == 0
+}
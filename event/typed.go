@@ -0,0 +1,98 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// Named позволяет событию задать свое имя вместо имени, полученного из типа.
+// Метод вызывается на нулевом значении типа и не должен зависеть от полей события
+type Named interface {
+	EventName() Name
+}
+
+// ListenerFunc позволяет использовать функцию в качестве ContextListener
+type ListenerFunc func(ctx context.Context, event interface{}) error
+
+func (f ListenerFunc) ListenContext(ctx context.Context, event interface{}) error {
+	return f(ctx, event)
+}
+
+// NameOf возвращает имя события типа T: результат EventName, если тип реализует Named,
+// иначе имя типа с именем пакета ("orders.Created"). Указатель и значение типа
+// соответствуют одному событию
+func NameOf[T any]() Name {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if named, ok := reflect.New(t).Interface().(Named); ok {
+		return named.EventName()
+	}
+
+	return Name(t.String())
+}
+
+// Subscribe подписывает типизированного слушателя на событие типа T
+// и регистрирует тип для восстановления события из хранилища. Слушатель значения
+// получает также события, отправленные указателем, и наоборот (указатель на копию события)
+func Subscribe[T any](d *Dispatcher, listener func(ctx context.Context, event T) error) (*Subscription, error) {
+	name := NameOf[T]()
+	d.registerType(name, reflect.TypeOf((*T)(nil)).Elem())
+
+	return d.SubscribeContext(name, ListenerFunc(func(ctx context.Context, event interface{}) error {
+		typed, ok := convert[T](event)
+		if !ok {
+			return fmt.Errorf("the '%s' event has type %T, expected %T", name, event, typed)
+		}
+
+		return listener(ctx, typed)
+	}), DefaultPriority)
+}
+
+// convert приводит событие к типу T, разыменовывая указатель или беря адрес копии значения
+func convert[T any](event interface{}) (T, bool) {
+	if typed, ok := event.(T); ok {
+		return typed, true
+	}
+
+	var typed T
+	value := reflect.ValueOf(event)
+	if !value.IsValid() {
+		return typed, false
+	}
+	target := reflect.TypeOf((*T)(nil)).Elem()
+	switch {
+	case value.Kind() == reflect.Pointer && value.Type().Elem() == target && !value.IsNil():
+		typed, ok := value.Elem().Interface().(T)
+		return typed, ok
+	case target.Kind() == reflect.Pointer && target.Elem() == value.Type():
+		pointer := reflect.New(value.Type())
+		pointer.Elem().Set(value)
+		typed, ok := pointer.Interface().(T)
+		return typed, ok
+	}
+
+	return typed, false
+}
+
+// SubscribeHandle подписывает на событие типа T вызов его метода Handle.
+// Диспетчер не вызывает Event.Handle неявно: событие обрабатывает себя само,
+// только если на него оформлена такая подписка
+func SubscribeHandle[T Event](d *Dispatcher) (*Subscription, error) {
+	return Subscribe(d, func(_ context.Context, event T) error {
+		event.Handle()
+		return nil
+	})
+}
+
+// Publish асинхронно отправляет событие типа T
+func Publish[T any](d DispatcherInterface, ctx context.Context, event T) error {
+	return d.DispatchContext(ctx, NameOf[T](), event)
+}
+
+// PublishSync синхронно отправляет событие типа T и возвращает ошибки слушателей
+func PublishSync[T any](d DispatcherInterface, ctx context.Context, event T) error {
+	return d.DispatchSync(ctx, NameOf[T](), event)
+}
@@ -0,0 +1,75 @@
+package event
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type orderCreated struct {
+	ID      int
+	handled chan int
+}
+
+func (e orderCreated) Handle() {
+	e.handled <- e.ID
+}
+
+type userDeleted struct{}
+
+func (userDeleted) EventName() Name {
+	return "user.deleted"
+}
+
+func TestNameOf(t *testing.T) {
+	assert.Equal(t, Name("event.orderCreated"), NameOf[orderCreated]())
+	assert.Equal(t, Name("event.orderCreated"), NameOf[*orderCreated]())
+	assert.Equal(t, Name("user.deleted"), NameOf[userDeleted]())
+	assert.Equal(t, Name("user.deleted"), NameOf[*userDeleted]())
+}
+
+func TestSubscribe_PublishSync(t *testing.T) {
+	d := NewDispatcher()
+	var got []int
+	_, err := Subscribe(d, func(ctx context.Context, event orderCreated) error {
+		got = append(got, event.ID)
+		return nil
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, PublishSync(d, context.Background(), orderCreated{ID: 42}))
+	assert.Equal(t, []int{42}, got)
+
+	assert.Error(t, d.DispatchSync(context.Background(), NameOf[orderCreated](), "not an order"))
+}
+
+func TestSubscribe_PointerAndValue(t *testing.T) {
+	d := NewDispatcher()
+	var values, pointers []int
+	_, err := Subscribe(d, func(ctx context.Context, event orderCreated) error {
+		values = append(values, event.ID)
+		return nil
+	})
+	assert.NoError(t, err)
+	_, err = Subscribe(d, func(ctx context.Context, event *orderCreated) error {
+		pointers = append(pointers, event.ID)
+		return nil
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, PublishSync(d, context.Background(), &orderCreated{ID: 1}))
+	assert.NoError(t, PublishSync(d, context.Background(), orderCreated{ID: 2}))
+	assert.Equal(t, []int{1, 2}, values)
+	assert.Equal(t, []int{1, 2}, pointers)
+}
+
+func TestSubscribeHandle(t *testing.T) {
+	d := NewDispatcher()
+	_, err := SubscribeHandle[orderCreated](d)
+	assert.NoError(t, err)
+
+	handled := make(chan int, 1)
+	assert.NoError(t, PublishSync(d, context.Background(), orderCreated{ID: 7, handled: handled}))
+	assert.Equal(t, 7, <-handled)
+}
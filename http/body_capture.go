@@ -0,0 +1,47 @@
+package http
+
+import (
+	"bytes"
+	"io"
+)
+
+// bodyCapture передает тело запроса обработчику, сохраняя первые limit байт для лога.
+// При limit <= 0 тело не сохраняется, считается только его размер
+type bodyCapture struct {
+	io.ReadCloser
+	buf   bytes.Buffer
+	limit int
+	read  int64
+	eof   bool
+	err   error
+}
+
+func newBodyCapture(body io.ReadCloser, limit int) *bodyCapture {
+	return &bodyCapture{ReadCloser: body, limit: limit}
+}
+
+func (b *bodyCapture) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.read += int64(n)
+	if free := b.limit - b.buf.Len(); free > 0 && n > 0 {
+		if n < free {
+			free = n
+		}
+		b.buf.Write(p[:free])
+	}
+	switch {
+	case err == io.EOF:
+		b.eof = true
+	case err != nil:
+		b.err = err
+	}
+
+	return n, err
+}
+
+// truncated сохранена не вся часть тела: тело длиннее limit или обработчик прочитал его не полностью.
+// Тело, не прочитанное обработчиком, для лога не дочитывается
+func (b *bodyCapture) truncated(contentLength int64) bool {
+	complete := b.eof || contentLength >= 0 && b.read >= contentLength
+	return !complete || b.read > int64(b.buf.Len())
+}
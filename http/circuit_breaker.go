@@ -0,0 +1,91 @@
+package http
+
+import (
+	"sync"
+	"time"
+)
+
+// BreakerOptions настройки размыкателя цепи для хоста
+type BreakerOptions struct {
+	// FailureThreshold число ошибок подряд, после которого запросы к хосту не выполняются, 0 - размыкатель отключен
+	FailureThreshold int
+	// OpenTimeout время, после которого выполняется пробный запрос
+	OpenTimeout time.Duration
+}
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// circuitBreaker размыкатель цепи одного хоста
+type circuitBreaker struct {
+	mu       sync.Mutex
+	options  BreakerOptions
+	state    breakerState
+	failures int
+	openedAt time.Time
+	now      func() time.Time
+}
+
+// allow проверяет, можно ли выполнить запрос. В полуоткрытом состоянии разрешается один пробный запрос
+func (b *circuitBreaker) allow() bool {
+	if b.options.FailureThreshold <= 0 {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if b.now().Sub(b.openedAt) < b.options.OpenTimeout {
+			return false
+		}
+		b.state = breakerHalfOpen
+		return true
+	case breakerHalfOpen:
+		return false
+	}
+	return true
+}
+
+// done учитывает результат запроса
+func (b *circuitBreaker) done(success bool) {
+	if b.options.FailureThreshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if success {
+		b.state = breakerClosed
+		b.failures = 0
+		return
+	}
+
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.options.FailureThreshold {
+		b.state = breakerOpen
+		b.openedAt = b.now()
+	}
+}
+
+// release завершает запрос без учета результата. Пробный запрос полуоткрытого состояния
+// разрешается повторить
+func (b *circuitBreaker) release() {
+	if b.options.FailureThreshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerHalfOpen {
+		b.state = breakerOpen
+	}
+}
@@ -0,0 +1,439 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/AeroAgency/golang-helpers-lib/metrics"
+	"github.com/AeroAgency/golang-helpers-lib/requestid"
+	"github.com/AeroAgency/golang-helpers-lib/tracing"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxErrorBodySize объем тела ответа с ошибкой, читаемый для разбора
+const maxErrorBodySize = 1024 * 1024
+
+// ErrCircuitOpen запрос не выполнен, так как размыкатель цепи хоста разомкнут
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// DefaultRetryStatuses статусы ответа, при которых запрос повторяется по умолчанию
+var DefaultRetryStatuses = []int{
+	http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout,
+}
+
+// ClientOptions настройки исходящего HTTP клиента
+type ClientOptions struct {
+	// Timeout время ожидания ответа, 0 - без ограничения
+	Timeout time.Duration
+	// HostTimeouts время ожидания ответа по хосту ("api.example.com" или "api.example.com:8080")
+	HostTimeouts map[string]time.Duration
+	// Retries число повторов идемпотентных запросов (GET, HEAD, OPTIONS, PUT, DELETE
+	// или с заголовком Idempotency-Key) при временной сетевой ошибке или статусе из RetryStatuses
+	Retries int
+	// Backoff задержка перед первым повтором, удваивается с каждым повтором до MaxBackoff
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// RetryStatuses статусы ответа, при которых запрос повторяется
+	RetryStatuses []int
+	// Breaker настройки размыкателя цепи, ошибкой считается сетевая ошибка, кроме отмены запроса вызывающим, или статус 5xx
+	Breaker BreakerOptions
+	// Metrics метрики HttpClientRequest и HttpClientRequestExecutionTime, nil - не собираются
+	Metrics metrics.Metrics
+	// Redaction скрытие чувствительных данных в логе
+	Redaction Redaction
+	// LogBodies логировать тела запросов и ответов с ошибкой
+	LogBodies bool
+	// Transport транспорт, выполняющий запросы, по умолчанию http.DefaultTransport
+	Transport http.RoundTripper
+}
+
+// DefaultClientOptions настройки по умолчанию: ожидание 30 секунд, 2 повтора,
+// размыкание цепи после 5 ошибок подряд на 30 секунд
+func DefaultClientOptions() ClientOptions {
+	return ClientOptions{
+		Timeout:       30 * time.Second,
+		Retries:       2,
+		Backoff:       100 * time.Millisecond,
+		MaxBackoff:    2 * time.Second,
+		RetryStatuses: DefaultRetryStatuses,
+		Breaker:       BreakerOptions{FailureThreshold: 5, OpenTimeout: 30 * time.Second},
+		Redaction:     DefaultRedaction(),
+	}
+}
+
+// Client исходящий HTTP клиент с повторами, размыкателем цепи, логированием, трассировкой и метриками
+type Client struct {
+	options  ClientOptions
+	client   *http.Client
+	tracer   trace.Tracer
+	mu       sync.Mutex
+	breakers map[string]*circuitBreaker
+	now      func() time.Time
+	sleep    func(ctx context.Context, d time.Duration) error
+}
+
+// NewClient Конструктор
+func NewClient(options ClientOptions) *Client {
+	transport := options.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	options.Redaction = options.Redaction.withDefaults()
+
+	return &Client{
+		options:  options,
+		client:   &http.Client{Transport: transport},
+		tracer:   otel.Tracer(tracing.InstrumentationName),
+		breakers: map[string]*circuitBreaker{},
+		now:      time.Now,
+		sleep:    sleep,
+	}
+}
+
+// Do выполняет запрос. Для ответа со статусом 4xx и 5xx возвращается ответ с прочитанным телом
+// и *Error, разобранная из тела в формате {applicationErrorCode, message, debug}.
+// Если размыкатель цепи хоста разомкнут, возвращается *Error со статусом 503, соответствующая ErrCircuitOpen
+func (c *Client) Do(request *http.Request) (*http.Response, error) {
+	ctx := request.Context()
+	breaker := c.breaker(request.URL.Host)
+	retries := 0
+	if c.retryable(request) {
+		retries = c.options.Retries
+	}
+
+	for attempt := 0; ; attempt++ {
+		if !breaker.allow() {
+			return nil, circuitOpenError(request.URL.Host)
+		}
+
+		response, err := c.attempt(request, attempt)
+		if err != nil && ctx.Err() != nil {
+			// отмена запроса вызывающим не говорит о недоступности хоста
+			breaker.release()
+		} else {
+			breaker.done(err == nil && response.StatusCode < http.StatusInternalServerError)
+		}
+
+		if attempt < retries && c.shouldRetry(ctx, response, err) {
+			if response != nil {
+				_, _ = io.Copy(io.Discard, response.Body)
+				_ = response.Body.Close()
+			}
+			if err := c.sleep(ctx, c.backoff(attempt)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+		if response.StatusCode >= http.StatusBadRequest {
+			return response, decodeError(request, response)
+		}
+		return response, nil
+	}
+}
+
+// attempt выполняет одну попытку запроса
+func (c *Client) attempt(request *http.Request, attempt int) (*http.Response, error) {
+	ctx := request.Context()
+	cancel := context.CancelFunc(func() {})
+	if timeout := c.timeout(request); timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+	ctx, span := c.tracer.Start(ctx, fmt.Sprintf("HTTP %s %s", request.Method, request.URL.Host),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.method", request.Method),
+			attribute.String("http.url", request.URL.Redacted()),
+			attribute.Int("http.attempt", attempt+1),
+		),
+	)
+	defer span.End()
+
+	req := request.Clone(ctx)
+	if attempt > 0 && request.GetBody != nil {
+		body, err := request.GetBody()
+		if err != nil {
+			cancel()
+			return nil, err
+		}
+		req.Body = body
+	}
+	tracing.Inject(ctx, propagation.HeaderCarrier(req.Header))
+	requestid.Inject(ctx, req.Header)
+
+	start := c.now()
+	response, err := c.client.Do(req)
+	latency := c.now().Sub(start)
+
+	status := "error"
+	if err == nil {
+		status = strconv.Itoa(response.StatusCode)
+		span.SetAttributes(attribute.Int("http.status_code", response.StatusCode))
+		if response.StatusCode >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(response.StatusCode))
+		}
+	} else {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	if c.options.Metrics != nil {
+		_ = c.options.Metrics.Inc(metrics.HttpClientRequest.Name, request.URL.Host, request.Method, status)
+		_ = c.options.Metrics.Observe(metrics.HttpClientRequestExecutionTime.Name, latency.Seconds(), request.URL.Host, request.Method)
+	}
+	if err != nil {
+		cancel()
+		c.log(ctx, request, attempt, latency, nil, err)
+		return nil, err
+	}
+
+	response.Body = cancelOnClose{ReadCloser: response.Body, cancel: cancel}
+	if response.StatusCode >= http.StatusBadRequest && c.options.LogBodies {
+		bufferBody(response)
+	}
+	c.log(ctx, request, attempt, latency, response, nil)
+	return response, nil
+}
+
+// log логирует попытку запроса в формате журнала HttpMiddleWare
+func (c *Client) log(ctx context.Context, request *http.Request, attempt int, latency time.Duration, response *http.Response, err error) {
+	logger := requestid.Logger(ctx, log.Logger)
+	event := logger.Info()
+	if err != nil || response.StatusCode >= http.StatusInternalServerError {
+		event = logger.Error()
+	}
+
+	requestParams := zerolog.Dict()
+	for k, v := range request.URL.Query() {
+		requestParams.Strs(k, c.options.Redaction.Query(k, v))
+	}
+	message := zerolog.Dict().
+		Str("http-method", request.Method).
+		Str("Host", request.URL.Host).
+		Str("Path", request.URL.Path).
+		Dur("Latency", latency).
+		Int("attempt", attempt+1).
+		Dict("request-params", requestParams)
+	if c.options.LogBodies && request.GetBody != nil {
+		if body, err := request.GetBody(); err == nil {
+			data, _ := io.ReadAll(io.LimitReader(body, maxErrorBodySize))
+			_ = body.Close()
+			message.Str("request-body", c.options.Redaction.Body(request.Header.Get(contentType), data))
+		}
+	}
+
+	event.Str("log-type", "outgoing-request").Str("X-Trace-Id", tracing.TraceId(ctx))
+	if err != nil {
+		message.Str("error", err.Error())
+	} else {
+		event.Int("return-code", response.StatusCode)
+		if body, ok := response.Body.(bufferedBody); ok {
+			message.Str("response-body", c.options.Redaction.Body(response.Header.Get(contentType), body.data))
+		}
+	}
+	event.Dict("message", message).Msg("")
+}
+
+func (c *Client) breaker(host string) *circuitBreaker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	breaker, ok := c.breakers[host]
+	if !ok {
+		breaker = &circuitBreaker{options: c.options.Breaker, now: c.now}
+		c.breakers[host] = breaker
+	}
+	return breaker
+}
+
+func (c *Client) timeout(request *http.Request) time.Duration {
+	if timeout, ok := c.options.HostTimeouts[request.URL.Host]; ok {
+		return timeout
+	}
+	if timeout, ok := c.options.HostTimeouts[request.URL.Hostname()]; ok {
+		return timeout
+	}
+	return c.options.Timeout
+}
+
+// retryable запрос идемпотентный и его тело можно отправить повторно
+func (c *Client) retryable(request *http.Request) bool {
+	if request.Body != nil && request.Body != http.NoBody && request.GetBody == nil {
+		return false
+	}
+	switch request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return request.Header.Get("Idempotency-Key") != ""
+}
+
+func (c *Client) shouldRetry(ctx context.Context, response *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return transient(err)
+	}
+	for _, status := range c.options.RetryStatuses {
+		if response.StatusCode == status {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Client) backoff(attempt int) time.Duration {
+	backoff := c.options.Backoff
+	for i := 0; i < attempt && backoff > 0; i++ {
+		if backoff > math.MaxInt64/2 || c.options.MaxBackoff > 0 && backoff >= c.options.MaxBackoff {
+			break
+		}
+		backoff *= 2
+	}
+	if c.options.MaxBackoff > 0 && backoff > c.options.MaxBackoff {
+		backoff = c.options.MaxBackoff
+	}
+	return backoff
+}
+
+// transient сетевая ошибка, которая может не повториться: тайм-аут, разрыв или отказ в соединении.
+// Ошибки сертификатов, неверный адрес и другие постоянные ошибки не повторяются
+func transient(err error) bool {
+	var unknownAuthority x509.UnknownAuthorityError
+	var invalidCertificate x509.CertificateInvalidError
+	var hostname x509.HostnameError
+	if errors.As(err, &unknownAuthority) || errors.As(err, &invalidCertificate) || errors.As(err, &hostname) {
+		return false
+	}
+
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsTimeout || dnsErr.IsTemporary
+	}
+
+	for _, target := range []error{
+		context.DeadlineExceeded, io.EOF, io.ErrUnexpectedEOF,
+		syscall.ECONNREFUSED, syscall.ECONNRESET, syscall.ECONNABORTED, syscall.EPIPE,
+	} {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	var opErr *net.OpError
+	return errors.As(err, &opErr)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// cancelOnClose отменяет контекст попытки запроса при закрытии тела ответа
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// bufferedBody прочитанное тело ответа
+type bufferedBody struct {
+	io.Reader
+	data []byte
+}
+
+func (b bufferedBody) Close() error {
+	return nil
+}
+
+// bufferBody читает тело ответа, заменяя его прочитанной копией
+func bufferBody(response *http.Response) []byte {
+	if body, ok := response.Body.(bufferedBody); ok {
+		return body.data
+	}
+
+	data, _ := io.ReadAll(io.LimitReader(response.Body, maxErrorBodySize))
+	_ = response.Body.Close()
+	response.Body = bufferedBody{Reader: bytes.NewReader(data), data: data}
+	return data
+}
+
+// decodeError разбирает тело ответа с ошибкой в формате {applicationErrorCode, message, debug}
+func decodeError(request *http.Request, response *http.Response) *Error {
+	data := bufferBody(response)
+
+	var body struct {
+		ApplicationErrorCode string          `json:"applicationErrorCode"`
+		Code                 string          `json:"code"`
+		Message              string          `json:"message"`
+		Debug                json.RawMessage `json:"debug"`
+	}
+	decoded := json.Unmarshal(data, &body) == nil && (body.ApplicationErrorCode != "" || body.Code != "" || body.Message != "")
+	if !decoded {
+		debug := strings.TrimSpace(string(data))
+		if debug == "" {
+			debug = fmt.Sprintf("%s %s: %s", request.Method, request.URL.Redacted(), response.Status)
+		}
+		return New(debug).SetHttpCode(response.StatusCode).SetMessage("%s", http.StatusText(response.StatusCode))
+	}
+
+	appCode := body.ApplicationErrorCode
+	if appCode == "" {
+		appCode = body.Code
+	}
+	var debug string
+	if err := json.Unmarshal(body.Debug, &debug); err != nil {
+		debug = string(body.Debug)
+	}
+	if debug == "" || debug == "null" || debug == "{}" {
+		debug = fmt.Sprintf("%s %s: %s", request.Method, request.URL.Redacted(), response.Status)
+	}
+
+	return New(debug).SetHttpCode(response.StatusCode).SetAppCode(appCode).SetMessage("%s", body.Message)
+}
+
+// circuitOpenError ошибка отказа в запросе разомкнутым размыкателем цепи
+func circuitOpenError(host string) *Error {
+	return (&Error{error: errors.Wrap(ErrCircuitOpen, host)}).
+		SetHttpCode(http.StatusServiceUnavailable).
+		SetAppCode("SERVICE_UNAVAILABLE").
+		SetMessage("service %s is unavailable", host)
+}
@@ -0,0 +1,359 @@
+package http
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/AeroAgency/golang-helpers-lib/metrics"
+	"github.com/AeroAgency/golang-helpers-lib/requestid"
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+type mockMetrics struct {
+	mu       sync.Mutex
+	inc      [][]string
+	observed []string
+}
+
+func (m *mockMetrics) Inc(metricName string, labelValues ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.inc = append(m.inc, append([]string{metricName}, labelValues...))
+	return nil
+}
+
+func (m *mockMetrics) Observe(metricName string, _ float64, _ ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.observed = append(m.observed, metricName)
+	return nil
+}
+
+func newTestClient(options ClientOptions) *Client {
+	client := NewClient(options)
+	client.sleep = func(ctx context.Context, d time.Duration) error { return ctx.Err() }
+	return client
+}
+
+func TestClient_RetriesIdempotentRequests(t *testing.T) {
+	captureLog(t)
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	m := &mockMetrics{}
+	options := DefaultClientOptions()
+	options.Metrics = m
+	client := newTestClient(options)
+
+	request, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	response, err := client.Do(request)
+	assert.NoError(t, err)
+	body, _ := io.ReadAll(response.Body)
+	assert.NoError(t, response.Body.Close())
+	assert.Equal(t, "ok", string(body))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	host := strings.TrimPrefix(server.URL, "http://")
+	assert.Equal(t, []string{metrics.HttpClientRequest.Name, host, http.MethodGet, "503"}, m.inc[0])
+	assert.Equal(t, []string{metrics.HttpClientRequest.Name, host, http.MethodGet, "200"}, m.inc[2])
+	assert.Len(t, m.observed, 3)
+}
+
+func TestClient_DoesNotRetryNonIdempotentRequests(t *testing.T) {
+	captureLog(t)
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := newTestClient(DefaultClientOptions())
+
+	request, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("{}"))
+	_, err := client.Do(request)
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	request, _ = http.NewRequest(http.MethodPost, server.URL, strings.NewReader("{}"))
+	request.Header.Set("Idempotency-Key", "key-1")
+	_, err = client.Do(request)
+	assert.Error(t, err)
+	assert.Equal(t, int32(4), atomic.LoadInt32(&calls))
+}
+
+func TestClient_ReplaysBodyOnRetry(t *testing.T) {
+	captureLog(t)
+	var bodies []string
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		bodies = append(bodies, string(body))
+		mu.Unlock()
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := newTestClient(DefaultClientOptions())
+
+	request, _ := http.NewRequest(http.MethodPut, server.URL, strings.NewReader(`{"id":1}`))
+	_, err := client.Do(request)
+	assert.Error(t, err)
+	assert.Equal(t, []string{`{"id":1}`, `{"id":1}`, `{"id":1}`}, bodies)
+}
+
+func TestClient_DecodesDownstreamError(t *testing.T) {
+	captureLog(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"applicationErrorCode":"NOT_FOUND","message":"Заказ не найден","debug":"order 1 not found"}`))
+	}))
+	defer server.Close()
+
+	client := newTestClient(DefaultClientOptions())
+
+	request, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	response, err := client.Do(request)
+	var appErr *Error
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, http.StatusNotFound, appErr.HttpCode())
+	assert.Equal(t, "NOT_FOUND", appErr.AppCode())
+	assert.Equal(t, "Заказ не найден", appErr.Message())
+	assert.Equal(t, "order 1 not found", appErr.Error())
+
+	body, _ := io.ReadAll(response.Body)
+	assert.Contains(t, string(body), "NOT_FOUND")
+}
+
+func TestClient_DecodesPlainTextError(t *testing.T) {
+	captureLog(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("bad input"))
+	}))
+	defer server.Close()
+
+	client := newTestClient(DefaultClientOptions())
+
+	request, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	_, err := client.Do(request)
+	var appErr *Error
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, http.StatusBadRequest, appErr.HttpCode())
+	assert.Equal(t, http.StatusText(http.StatusBadRequest), appErr.Message())
+	assert.Equal(t, "bad input", appErr.Error())
+}
+
+func TestClient_CircuitBreaker(t *testing.T) {
+	captureLog(t)
+	var calls int32
+	var healthy atomic.Value
+	healthy.Store(false)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if !healthy.Load().(bool) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	now := time.Now()
+	options := DefaultClientOptions()
+	options.Retries = 0
+	options.Breaker = BreakerOptions{FailureThreshold: 2, OpenTimeout: time.Minute}
+	client := newTestClient(options)
+	client.now = func() time.Time { return now }
+
+	do := func() error {
+		request, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+		response, err := client.Do(request)
+		if response != nil {
+			_ = response.Body.Close()
+		}
+		return err
+	}
+
+	assert.Error(t, do())
+	assert.Error(t, do())
+	err := do()
+	assert.True(t, errors.Is(err, ErrCircuitOpen))
+	var appErr *Error
+	assert.True(t, errors.As(err, &appErr))
+	assert.Equal(t, http.StatusServiceUnavailable, appErr.HttpCode())
+	assert.Equal(t, "SERVICE_UNAVAILABLE", appErr.AppCode())
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+
+	now = now.Add(time.Minute)
+	healthy.Store(true)
+	assert.NoError(t, do())
+	assert.NoError(t, do())
+	assert.Equal(t, int32(4), atomic.LoadInt32(&calls))
+}
+
+func TestClient_HostTimeout(t *testing.T) {
+	captureLog(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer server.Close()
+
+	options := DefaultClientOptions()
+	options.Retries = 0
+	options.HostTimeouts = map[string]time.Duration{"127.0.0.1": 10 * time.Millisecond}
+	client := newTestClient(options)
+
+	request, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	_, err := client.Do(request)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestClient_InjectsHeadersAndLogs(t *testing.T) {
+	buf := captureLog(t)
+	var header http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+	}))
+	defer server.Close()
+
+	ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "handler")
+	defer span.End()
+	ctx = requestid.NewContext(ctx, requestid.Ids{RequestId: "req-1", CorrelationId: "chain-1"})
+
+	client := newTestClient(DefaultClientOptions())
+
+	request, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/orders?token=secret&page=2", nil)
+	response, err := client.Do(request)
+	assert.NoError(t, err)
+	assert.NoError(t, response.Body.Close())
+
+	assert.Contains(t, header.Get("traceparent"), span.SpanContext().TraceID().String())
+	assert.Equal(t, "req-1", header.Get(requestid.HeaderRequestId))
+	assert.Equal(t, "chain-1", header.Get(requestid.HeaderCorrelationId))
+
+	entries := buf.entries(t)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, "outgoing-request", entries[0]["log-type"])
+	assert.Equal(t, float64(http.StatusOK), entries[0]["return-code"])
+	assert.Equal(t, "req-1", entries[0]["request_id"])
+	assert.Equal(t, span.SpanContext().TraceID().String(), entries[0]["X-Trace-Id"])
+	message := entries[0]["message"].(map[string]interface{})
+	assert.Equal(t, "/orders", message["Path"])
+	params := message["request-params"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"2"}, params["page"])
+	assert.NotEqual(t, []interface{}{"secret"}, params["token"])
+}
+
+func TestClient_Backoff(t *testing.T) {
+	client := NewClient(ClientOptions{Backoff: 100 * time.Millisecond, MaxBackoff: time.Second})
+	assert.Equal(t, 100*time.Millisecond, client.backoff(0))
+	assert.Equal(t, 400*time.Millisecond, client.backoff(2))
+	assert.Equal(t, time.Second, client.backoff(4))
+	assert.Equal(t, time.Second, client.backoff(100))
+
+	client = NewClient(ClientOptions{MaxBackoff: time.Second})
+	assert.Equal(t, time.Duration(0), client.backoff(3))
+
+	client = NewClient(ClientOptions{Backoff: time.Second})
+	assert.True(t, client.backoff(100) > 0)
+}
+
+func TestTransient(t *testing.T) {
+	assert.True(t, transient(&url.Error{Op: "Get", Err: syscall.ECONNREFUSED}))
+	assert.True(t, transient(&url.Error{Op: "Get", Err: io.ErrUnexpectedEOF}))
+	assert.True(t, transient(&url.Error{Op: "Get", Err: context.DeadlineExceeded}))
+	assert.True(t, transient(&net.OpError{Op: "read", Err: errors.New("broken")}))
+	assert.True(t, transient(&net.DNSError{Err: "timeout", IsTimeout: true}))
+
+	assert.False(t, transient(&url.Error{Op: "Get", Err: x509.UnknownAuthorityError{}}))
+	assert.False(t, transient(&url.Error{Op: "Get", Err: x509.HostnameError{}}))
+	assert.False(t, transient(&net.OpError{Op: "dial", Err: &net.DNSError{Err: "no such host", IsNotFound: true}}))
+	assert.False(t, transient(&url.Error{Op: "Get", Err: errors.New(`unsupported protocol scheme "ftp"`)}))
+}
+
+func TestClient_DoesNotRetryPermanentErrors(t *testing.T) {
+	captureLog(t)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	var attempts int32
+	options := DefaultClientOptions()
+	options.Transport = roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		atomic.AddInt32(&attempts, 1)
+		return http.DefaultTransport.RoundTrip(r)
+	})
+	client := newTestClient(options)
+
+	request, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	_, err := client.Do(request)
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&attempts))
+}
+
+func TestClient_CancellationDoesNotOpenBreaker(t *testing.T) {
+	captureLog(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	options := DefaultClientOptions()
+	options.Breaker = BreakerOptions{FailureThreshold: 1, OpenTimeout: time.Minute}
+	client := newTestClient(options)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i := 0; i < 3; i++ {
+		request, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		_, err := client.Do(request)
+		assert.True(t, errors.Is(err, context.Canceled))
+	}
+
+	request, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	response, err := client.Do(request)
+	assert.NoError(t, err)
+	assert.NoError(t, response.Body.Close())
+}
+
+func TestCircuitBreaker_Release(t *testing.T) {
+	now := time.Now()
+	breaker := &circuitBreaker{options: BreakerOptions{FailureThreshold: 1, OpenTimeout: time.Minute}, now: func() time.Time { return now }}
+
+	breaker.done(false)
+	assert.False(t, breaker.allow())
+	now = now.Add(time.Minute)
+	assert.True(t, breaker.allow())
+	assert.False(t, breaker.allow())
+	breaker.release()
+	assert.True(t, breaker.allow())
+	breaker.done(true)
+	assert.True(t, breaker.allow())
+}
+
+type roundTripFunc func(r *http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
@@ -64,3 +64,8 @@ func (e *Error) MarshalJSON() ([]byte, error) {
 	}
 	return json.Marshal(m)
 }
+
+// Unwrap возвращает исходную ошибку
+func (e *Error) Unwrap() error {
+	return e.error
+}
@@ -4,10 +4,12 @@ import (
 	"bytes"
 	"fmt"
 	tracerAdapter "github.com/AeroAgency/go-gin-tracer"
+	appLogger "github.com/AeroAgency/golang-helpers-lib/logger"
+	"github.com/AeroAgency/golang-helpers-lib/requestid"
+	"github.com/AeroAgency/golang-helpers-lib/tracing"
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
-	"io"
 	"net/http"
 	"strings"
 	"time"
@@ -15,102 +17,268 @@ import (
 
 // duplicate strings in code
 const (
-	userAgent = "User-Agent"
+	userAgent   = "User-Agent"
+	contentType = "Content-Type"
 )
 
-// exceptions - User-Agent exceptions
-var (
-	exception  bool
-	exceptions = [...]string{
-		"kube-probe", "Prometheus",
+// DefaultMaxBodyCapture объем тела запроса, сохраняемый для лога по умолчанию
+const DefaultMaxBodyCapture = 64 * 1024
+
+// DefaultBodyMethods методы запросов, тело которых логируется по умолчанию
+var DefaultBodyMethods = []string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// MiddlewareOptions настройки логирования запросов и ответов
+type MiddlewareOptions struct {
+	// AccessLog правила пропуска запросов, выборка, уровни и поля записей
+	AccessLog appLogger.AccessLogOptions
+	// Redaction скрытие чувствительных данных, незаданные списки берутся из DefaultRedaction
+	Redaction Redaction
+	// BodyMethods методы запросов, тело которых логируется, по умолчанию DefaultBodyMethods
+	BodyMethods []string
+	// MaxBodyCapture объем тела запроса и ответа, сохраняемый для лога, по умолчанию DefaultMaxBodyCapture.
+	// Обработчик получает тело запроса полностью, клиент - тело ответа
+	MaxBodyCapture int
+}
+
+// DefaultMiddlewareOptions настройки по умолчанию: запросы kube-probe и Prometheus не логируются,
+// чувствительные данные скрываются по DefaultRedaction
+func DefaultMiddlewareOptions() MiddlewareOptions {
+	return MiddlewareOptions{
+		AccessLog:      appLogger.DefaultAccessLogOptions(),
+		Redaction:      DefaultRedaction(),
+		BodyMethods:    DefaultBodyMethods,
+		MaxBodyCapture: DefaultMaxBodyCapture,
 	}
-)
+}
 
+// bodyLogWriter сохраняет для лога первые limit байт ответа. Ответ с типом содержимого,
+// который не логируется, не сохраняется, считается только его размер
 type bodyLogWriter struct {
 	gin.ResponseWriter
-	body *bytes.Buffer
+	body      bytes.Buffer
+	limit     int
+	redaction Redaction
+	started   bool
+	skip      bool
+	written   int64
+}
+
+func (w *bodyLogWriter) Write(b []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.skip = !w.redaction.loggableType(w.Header().Get(contentType))
+	}
+
+	n, err := w.ResponseWriter.Write(b)
+	w.written += int64(n)
+	if free := w.limit - w.body.Len(); !w.skip && free > 0 {
+		if n < free {
+			free = n
+		}
+		w.body.Write(b[:free])
+	}
+	return n, err
 }
 
-func (w bodyLogWriter) Write(b []byte) (int, error) {
-	w.body.Write(b)
-	return w.ResponseWriter.Write(b)
+func (w *bodyLogWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
 }
 
-// HttpMiddleWare -
+// loggedBody возвращает тело ответа для лога
+func (w *bodyLogWriter) loggedBody() string {
+	responseType := w.Header().Get(contentType)
+	switch {
+	case w.skip:
+		return fmt.Sprintf("[body omitted: %s, %d bytes]", responseType, w.written)
+	case w.written > int64(w.body.Len()):
+		return w.redaction.Partial(responseType, w.body.Bytes())
+	}
+	return w.redaction.Body(responseType, w.body.Bytes())
+}
+
+// HttpMiddleWare логирует запросы с настройками DefaultMiddlewareOptions
 func HttpMiddleWare() gin.HandlerFunc {
+	return HttpMiddleWareWithOptions(DefaultMiddlewareOptions())
+}
+
+// HttpMiddleWareWithOptions логирует запросы с настройками options
+func HttpMiddleWareWithOptions(options MiddlewareOptions) gin.HandlerFunc {
+	accessLog := appLogger.NewAccessLog(options.AccessLog)
+	redaction := options.Redaction.withDefaults()
+	maxBodyCapture := options.MaxBodyCapture
+	if maxBodyCapture <= 0 {
+		maxBodyCapture = DefaultMaxBodyCapture
+	}
+	bodyMethods := options.BodyMethods
+	if bodyMethods == nil {
+		bodyMethods = DefaultBodyMethods
+	}
+
 	return func(c *gin.Context) {
-		methodId := fmt.Sprintf("handler %s:%s", c.Request.Method, c.FullPath())
-		tracer := tracerAdapter.NewTracer(methodId)
-		defer tracer.Close()
+		// Спан, созданный tracing.GinMiddleware, используется вместо спана go-gin-tracer
+		tracer := tracing.FromGin(c)
+		if _, ok := tracer.(tracing.Noop); ok {
+			methodId := fmt.Sprintf("handler %s:%s", c.Request.Method, c.FullPath())
+			ginTracer := tracerAdapter.NewTracer(methodId)
+			defer ginTracer.Close()
+			tracer = ginTracer
+		}
 		t1 := time.Now()
-		var requestBody string
-		if c.Request.Method == http.MethodPost {
-			buf, _ := io.ReadAll(c.Request.Body)
-			rdr1 := io.NopCloser(bytes.NewBuffer(buf))
-			rdr2 := io.NopCloser(bytes.NewBuffer(buf)) //We have to create a new Buffer, because rdr1 will be read.
-			r, _ := io.ReadAll(rdr1)
-			requestBody = string(r)
-			tracer.LogData("[Request body]", requestBody)
-			c.Request.Body = rdr2
+		var capture *bodyCapture
+		if containsFold(bodyMethods, c.Request.Method) && c.Request.Body != nil && c.Request.Body != http.NoBody {
+			// Тело типов, которые не логируются (multipart, бинарные данные), не сохраняется
+			limit := 0
+			if redaction.loggableType(c.GetHeader(contentType)) {
+				limit = maxBodyCapture
+			}
+			capture = newBodyCapture(c.Request.Body, limit)
+			c.Request.Body = capture
 		}
-		defer func(requestBody string) {
-			for i := range exceptions {
-				if strings.HasPrefix(c.Request.Header.Get(userAgent), exceptions[i]) {
-					exception = true
-				}
+		defer func() {
+			requestBody := capturedBody(capture, c.GetHeader(contentType), c.Request.ContentLength, redaction)
+			if requestBody != "" {
+				tracer.LogData("[Request body]", requestBody)
 			}
-			if !exception {
-				requestParams := zerolog.Dict()
-				allQueryParams := c.Request.URL.Query()
-				for k, v := range allQueryParams {
-					requestParams.Strs(k, v)
-					queryVal := strings.Join(v, ", ")
-					tracer.Log(fmt.Sprintf("Query-param [%s]", k), queryVal)
-				}
-				for k, values := range c.Request.Header {
-					// Loop over all values for the name.
-					headerVal := strings.Join(values, ", ")
-					tracer.Log(fmt.Sprintf("Header [%s]", k), headerVal)
+			if capture != nil && capture.err != nil {
+				tracer.Log("[Request body error]", capture.err.Error())
+			}
+
+			if accessLog.Skip(c) {
+				return
+			}
+
+			requestParams := zerolog.Dict()
+			allQueryParams := c.Request.URL.Query()
+			for k, v := range allQueryParams {
+				v = redaction.Query(k, v)
+				requestParams.Strs(k, v)
+				queryVal := strings.Join(v, ", ")
+				tracer.Log(fmt.Sprintf("Query-param [%s]", k), queryVal)
+			}
+			for k, values := range c.Request.Header {
+				// Loop over all values for the name.
+				headerVal := redaction.Header(k, values)
+				tracer.Log(fmt.Sprintf("Header [%s]", k), headerVal)
+			}
+
+			event := accessLog.Event(log.Logger, c.Writer.Status())
+			if event == nil {
+				return
+			}
+			message := zerolog.Dict()
+			if accessLog.Field(appLogger.FieldMethod) {
+				message.Str(appLogger.FieldMethod, c.Request.Method)
+			}
+			if accessLog.Field(appLogger.FieldPath) {
+				message.Str(appLogger.FieldPath, c.FullPath())
+			}
+			if accessLog.Field(appLogger.FieldUserAgent) {
+				message.Str(userAgent, c.Request.Header.Get(userAgent))
+			}
+			if accessLog.Field(appLogger.FieldLatency) {
+				message.Dur(appLogger.FieldLatency, time.Since(t1))
+			}
+			if accessLog.Field(appLogger.FieldRequestParams) {
+				message.Dict(appLogger.FieldRequestParams, requestParams)
+			}
+			if accessLog.Field(appLogger.FieldRequestBody) {
+				message.Str(appLogger.FieldRequestBody, requestBody)
+				if capture != nil && capture.err != nil {
+					message.Str(appLogger.FieldRequestBody+"-error", capture.err.Error())
 				}
-				log.Info().
-					Str("log-type", "request").
-					Int("return-code", c.Writer.Status()).
-					Str("X-Trace-Id", c.Writer.Header().Get("X-Trace-Id")).
-					Dict("message", zerolog.Dict().
-						Str("http-method", c.Request.Method).
-						Str("Path", c.FullPath()).
-						Str(userAgent, c.Request.Header.Get(userAgent)).
-						Dur("Latency", time.Since(t1)).
-						Dict("request-params", requestParams).
-						Str("request-body", requestBody),
-					).Msg("")
-			}
-			exception = false
-		}(requestBody)
-		c.Set("tracer", tracer)
+			}
+
+			event.Str("log-type", "request")
+			if accessLog.Field(appLogger.FieldReturnCode) {
+				event.Int(appLogger.FieldReturnCode, c.Writer.Status())
+			}
+			if accessLog.Field(appLogger.FieldTraceId) {
+				event.Str(appLogger.FieldTraceId, c.Writer.Header().Get("X-Trace-Id"))
+			}
+			if ids := requestid.FromGin(c); ids.RequestId != "" && accessLog.Field(appLogger.FieldRequestId) {
+				event.Str(appLogger.FieldRequestId, ids.RequestId)
+			}
+			event.Dict("message", message).Msg("")
+		}()
+		c.Set(tracing.GinKey, tracer)
 		c.Next()
 	}
 }
 
+// HttpRespLogMiddleWare логирует ответы с настройками DefaultMiddlewareOptions, если logResponses
 func HttpRespLogMiddleWare(logResponses bool) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		if logResponses {
-			blw := &bodyLogWriter{body: bytes.NewBufferString(""), ResponseWriter: c.Writer}
-			c.Writer = blw
-			c.Next()
-			tracer, _ := c.MustGet("tracer").(tracerAdapter.TracerInterface)
-			responseBody := blw.body.String()
-			tracer.Log("[Response Body]", responseBody)
-			log.Info().
-				Str("log-type", "response").
-				Int("return-code", c.Writer.Status()).
-				Str("X-Trace-Id", c.Writer.Header().Get("X-Trace-Id")).
-				Dict("message", zerolog.Dict().
-					Str("response-body", responseBody),
-				).Msg("")
-
-		} else {
+	if !logResponses {
+		return func(c *gin.Context) {
 			c.Next()
 		}
 	}
+
+	return HttpRespLogMiddleWareWithOptions(DefaultMiddlewareOptions())
+}
+
+// HttpRespLogMiddleWareWithOptions логирует ответы с настройками options
+func HttpRespLogMiddleWareWithOptions(options MiddlewareOptions) gin.HandlerFunc {
+	accessLog := appLogger.NewAccessLog(options.AccessLog)
+	redaction := options.Redaction.withDefaults()
+	maxBodyCapture := options.MaxBodyCapture
+	if maxBodyCapture <= 0 {
+		maxBodyCapture = DefaultMaxBodyCapture
+	}
+
+	return func(c *gin.Context) {
+		blw := &bodyLogWriter{ResponseWriter: c.Writer, limit: maxBodyCapture, redaction: redaction}
+		c.Writer = blw
+		c.Next()
+		if accessLog.Skip(c) {
+			return
+		}
+
+		responseBody := blw.loggedBody()
+		tracing.FromGin(c).Log("[Response Body]", responseBody)
+
+		event := accessLog.Event(log.Logger, c.Writer.Status())
+		if event == nil {
+			return
+		}
+		event.Str("log-type", "response")
+		if accessLog.Field(appLogger.FieldReturnCode) {
+			event.Int(appLogger.FieldReturnCode, c.Writer.Status())
+		}
+		if accessLog.Field(appLogger.FieldTraceId) {
+			event.Str(appLogger.FieldTraceId, c.Writer.Header().Get("X-Trace-Id"))
+		}
+		if ids := requestid.FromGin(c); ids.RequestId != "" && accessLog.Field(appLogger.FieldRequestId) {
+			event.Str(appLogger.FieldRequestId, ids.RequestId)
+		}
+		message := zerolog.Dict()
+		if accessLog.Field(appLogger.FieldResponseBody) {
+			message.Str(appLogger.FieldResponseBody, responseBody)
+		}
+		event.Dict("message", message).Msg("")
+	}
+}
+
+// capturedBody возвращает для лога часть тела запроса, прочитанную обработчиком
+func capturedBody(capture *bodyCapture, contentType string, contentLength int64, redaction Redaction) string {
+	if capture == nil {
+		return ""
+	}
+	if capture.limit <= 0 {
+		size := contentLength
+		if size < 0 {
+			size = capture.read
+		}
+		return fmt.Sprintf("[body omitted: %s, %d bytes]", contentType, size)
+	}
+
+	if !capture.truncated(contentLength) {
+		return redaction.Body(contentType, capture.buf.Bytes())
+	}
+	if capture.read == 0 {
+		if contentLength < 0 {
+			return fmt.Sprintf("[body omitted: %s, not read]", contentType)
+		}
+		return fmt.Sprintf("[body omitted: %s, %d bytes not read]", contentType, contentLength)
+	}
+	return redaction.Partial(contentType, capture.buf.Bytes())
 }
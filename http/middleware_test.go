@@ -0,0 +1,243 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	appLogger "github.com/AeroAgency/golang-helpers-lib/logger"
+	"github.com/AeroAgency/golang-helpers-lib/tracing"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// syncBuffer буфер для записи лога из нескольких горутин
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) entries(t *testing.T) []map[string]interface{} {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(b.buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]interface{}
+		assert.NoError(t, json.Unmarshal([]byte(line), &entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func captureLog(t *testing.T) *syncBuffer {
+	buf := &syncBuffer{}
+	logger := log.Logger
+	log.Logger = zerolog.New(buf)
+	t.Cleanup(func() { log.Logger = logger })
+	return buf
+}
+
+func newRouter(middlewares ...gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(tracing.GinMiddleware(sdktrace.NewTracerProvider().Tracer("test")))
+	router.Use(middlewares...)
+	router.POST("/orders", func(c *gin.Context) {
+		_, _ = io.ReadAll(c.Request.Body)
+		c.JSON(http.StatusCreated, gin.H{"id": 42, "token": "secret"})
+	})
+	router.GET("/health", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func TestHttpMiddleWareWithOptions(t *testing.T) {
+	buf := captureLog(t)
+	options := DefaultMiddlewareOptions()
+	options.AccessLog.SkipPaths = []string{"/health"}
+	options.AccessLog.Fields = []string{appLogger.FieldPath, appLogger.FieldReturnCode, appLogger.FieldRequestBody, appLogger.FieldResponseBody}
+	router := newRouter(HttpMiddleWareWithOptions(options), HttpRespLogMiddleWareWithOptions(options))
+
+	request := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"password":"qwerty","amount":10}`))
+	request.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(httptest.NewRecorder(), request)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	entries := buf.entries(t)
+	assert.Len(t, entries, 2)
+	assert.Equal(t, map[string]interface{}{
+		"level":       "info",
+		"log-type":    "response",
+		"return-code": float64(http.StatusCreated),
+		"message":     map[string]interface{}{"response-body": `{"id":42,"token":"***"}`},
+	}, entries[0])
+	assert.Equal(t, map[string]interface{}{
+		"level":       "info",
+		"log-type":    "request",
+		"return-code": float64(http.StatusCreated),
+		"message": map[string]interface{}{
+			"Path":         "/orders",
+			"request-body": `{"amount":10,"password":"***"}`,
+		},
+	}, entries[1])
+}
+
+func TestHttpMiddleWareWithOptions_ZeroRedaction(t *testing.T) {
+	buf := captureLog(t)
+	options := MiddlewareOptions{AccessLog: appLogger.AccessLogOptions{Fields: []string{appLogger.FieldRequestBody}}}
+	router := newRouter(HttpMiddleWareWithOptions(options))
+
+	request := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"password":"qwerty"}`))
+	request.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(httptest.NewRecorder(), request)
+
+	entries := buf.entries(t)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, map[string]interface{}{"request-body": `{"password":"***"}`}, entries[0]["message"])
+}
+
+func TestHttpMiddleWare_Concurrent(t *testing.T) {
+	buf := captureLog(t)
+	router := newRouter(HttpMiddleWare())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			request := httptest.NewRequest(http.MethodGet, "/health", nil)
+			if i%2 == 0 {
+				request.Header.Set("User-Agent", "kube-probe/1.27")
+			}
+			router.ServeHTTP(httptest.NewRecorder(), request)
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Len(t, buf.entries(t), 10)
+}
+
+func TestHttpMiddleWare_RequestBody(t *testing.T) {
+	options := DefaultMiddlewareOptions()
+	options.MaxBodyCapture = 16
+	options.AccessLog.Fields = []string{appLogger.FieldRequestBody}
+
+	var received []string
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(tracing.GinMiddleware(sdktrace.NewTracerProvider().Tracer("test")), HttpMiddleWareWithOptions(options))
+	handler := func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		assert.NoError(t, err)
+		received = append(received, string(body))
+		c.Status(http.StatusNoContent)
+	}
+	router.PUT("/files", handler)
+	router.PATCH("/files", func(c *gin.Context) {
+		c.Status(http.StatusNoContent)
+	})
+	router.DELETE("/files", func(c *gin.Context) {
+		_, _ = io.ReadFull(c.Request.Body, make([]byte, 5))
+		c.Status(http.StatusNoContent)
+	})
+	router.GET("/files", handler)
+
+	send := func(method, contentType, body string) string {
+		buf := captureLog(t)
+		request := httptest.NewRequest(method, "/files", strings.NewReader(body))
+		request.Header.Set("Content-Type", contentType)
+		router.ServeHTTP(httptest.NewRecorder(), request)
+
+		entries := buf.entries(t)
+		assert.Len(t, entries, 1)
+		logged, _ := entries[0]["message"].(map[string]interface{})[appLogger.FieldRequestBody].(string)
+		return logged
+	}
+
+	long := strings.Repeat("a", 40)
+	assert.Equal(t, "aaaaaaaaaaaaaaaa...[truncated]", send(http.MethodPut, "text/plain", long))
+	assert.Equal(t, long, received[0])
+
+	assert.Equal(t, `[body omitted: application/json, more than 16 bytes]`,
+		send(http.MethodPut, "application/json", `{"password":"qwerty","amount":10}`))
+	assert.Equal(t, `{"password":"qwerty","amount":10}`, received[1])
+
+	assert.Equal(t, `[body omitted: multipart/form-data; boundary=x, 40 bytes]`,
+		send(http.MethodPut, "multipart/form-data; boundary=x", long))
+	assert.Equal(t, long, received[2])
+
+	// Тело, не прочитанное обработчиком, не дочитывается для лога
+	assert.Equal(t, `[body omitted: application/json, 15 bytes not read]`, send(http.MethodPatch, "application/json", `{"token":"abc"}`))
+	assert.Equal(t, "hello...[truncated]", send(http.MethodDelete, "text/plain", "hello world"))
+
+	assert.Equal(t, "", send(http.MethodGet, "text/plain", "query"))
+}
+
+func TestHttpRespLogMiddleWare_ResponseBody(t *testing.T) {
+	options := DefaultMiddlewareOptions()
+	options.MaxBodyCapture = 16
+	options.AccessLog.Fields = []string{appLogger.FieldResponseBody}
+
+	var writer *bodyLogWriter
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(HttpRespLogMiddleWareWithOptions(options), func(c *gin.Context) {
+		writer = c.Writer.(*bodyLogWriter)
+	})
+	router.GET("/file", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/octet-stream", make([]byte, 1000))
+	})
+	router.GET("/text", func(c *gin.Context) {
+		c.String(http.StatusOK, strings.Repeat("a", 1000))
+	})
+
+	send := func(path string) string {
+		buf := captureLog(t)
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+		entries := buf.entries(t)
+		assert.Len(t, entries, 1)
+		logged, _ := entries[0]["message"].(map[string]interface{})[appLogger.FieldResponseBody].(string)
+		return logged
+	}
+
+	assert.Equal(t, "[body omitted: application/octet-stream, 1000 bytes]", send("/file"))
+	assert.Equal(t, 0, writer.body.Len())
+
+	assert.Equal(t, strings.Repeat("a", 16)+"...[truncated]", send("/text"))
+	assert.Equal(t, 16, writer.body.Len())
+}
+
+type failingReader struct{}
+
+func (r failingReader) Read(p []byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+func TestBodyCapture_Error(t *testing.T) {
+	capture := newBodyCapture(io.NopCloser(io.MultiReader(strings.NewReader("partial"), failingReader{})), 100)
+
+	_, err := io.ReadAll(capture)
+	assert.EqualError(t, err, "connection reset")
+	assert.EqualError(t, capture.err, "connection reset")
+	assert.Equal(t, "partial", capture.buf.String())
+}
@@ -0,0 +1,264 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultMask значение, которым заменяются скрытые данные
+const DefaultMask = "***"
+
+// Redaction настройки скрытия чувствительных данных в логах запросов и ответов.
+// Списки, равные nil, заменяются списками DefaultRedaction, пустой список отключает скрытие
+type Redaction struct {
+	// Headers заголовки, значения которых скрываются, без учета регистра
+	Headers []string
+	// QueryParams параметры запроса, значения которых скрываются, без учета регистра
+	QueryParams []string
+	// BodyFields пути полей JSON тела через точку: "user.password", "cards.*.number".
+	// "*" соответствует любому полю или элементу массива. Путь из одного поля ("password")
+	// соответствует полю на любом уровне вложенности, а также полю формы
+	BodyFields []string
+	// ContentTypes типы содержимого, тело которых логируется (по префиксу).
+	// Тело без типа логируется, если является текстом в UTF-8
+	ContentTypes []string
+	// MaxBodySize размер тела в логе, после которого оно обрезается, 0 - без ограничения
+	MaxBodySize int
+	// Mask значение, которым заменяются скрытые данные, по умолчанию DefaultMask
+	Mask string
+}
+
+// DefaultRedaction скрывает заголовки авторизации и cookie, пароли, токены и номера карт,
+// логирует только текстовые тела размером до 10 КБ
+func DefaultRedaction() Redaction {
+	return Redaction{
+		Headers:     []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key"},
+		QueryParams: []string{"token", "access_token", "api_key", "password"},
+		BodyFields: []string{
+			"password", "newPassword", "oldPassword", "token", "accessToken", "refreshToken",
+			"access_token", "refresh_token", "secret", "cardNumber", "card_number", "pan", "cvv", "cvc",
+		},
+		ContentTypes: []string{
+			"application/json", "application/problem+json", "application/x-www-form-urlencoded",
+			"application/xml", "text/",
+		},
+		MaxBodySize: 10 * 1024,
+		Mask:        DefaultMask,
+	}
+}
+
+// withDefaults заменяет списки, равные nil, списками DefaultRedaction
+func (r Redaction) withDefaults() Redaction {
+	defaults := DefaultRedaction()
+	if r.Headers == nil {
+		r.Headers = defaults.Headers
+	}
+	if r.QueryParams == nil {
+		r.QueryParams = defaults.QueryParams
+	}
+	if r.BodyFields == nil {
+		r.BodyFields = defaults.BodyFields
+	}
+	if r.ContentTypes == nil {
+		r.ContentTypes = defaults.ContentTypes
+	}
+	return r
+}
+
+func (r Redaction) mask() string {
+	if r.Mask == "" {
+		return DefaultMask
+	}
+	return r.Mask
+}
+
+// Header возвращает значение заголовка для лога
+func (r Redaction) Header(name string, values []string) string {
+	if containsFold(r.Headers, name) {
+		return r.mask()
+	}
+	return strings.Join(values, ", ")
+}
+
+// Query возвращает значения параметра запроса для лога
+func (r Redaction) Query(name string, values []string) []string {
+	if !containsFold(r.QueryParams, name) {
+		return values
+	}
+
+	masked := make([]string, len(values))
+	for i := range masked {
+		masked[i] = r.mask()
+	}
+	return masked
+}
+
+// Body возвращает тело запроса или ответа для лога
+func (r Redaction) Body(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if !r.loggable(mediaType, body) {
+		return fmt.Sprintf("[body omitted: %s, %d bytes]", contentType, len(body))
+	}
+
+	masked := true
+	switch {
+	case strings.HasSuffix(mediaType, "json"):
+		body, masked = r.maskJson(body)
+	case mediaType == "application/x-www-form-urlencoded":
+		body, masked = r.maskForm(body)
+	}
+	if !masked {
+		// тело, которое не удалось разобрать, не логируется, так как скрыть в нем поля нельзя
+		return fmt.Sprintf("[body omitted: %s, %d bytes]", contentType, len(body))
+	}
+
+	if r.MaxBodySize > 0 && len(body) > r.MaxBodySize {
+		return fmt.Sprintf("%s...[truncated, %d bytes]", body[:r.MaxBodySize], len(body))
+	}
+	return string(body)
+}
+
+// Partial возвращает для лога начало тела, обрезанного при чтении. Тела JSON и форм
+// не логируются, так как скрыть поля в обрезанном документе нельзя
+func (r Redaction) Partial(contentType string, body []byte) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	structured := strings.HasSuffix(mediaType, "json") || mediaType == "application/x-www-form-urlencoded"
+	if structured || !r.loggable(mediaType, body) {
+		return fmt.Sprintf("[body omitted: %s, more than %d bytes]", contentType, len(body))
+	}
+
+	partial := r.Body(contentType, body)
+	if r.MaxBodySize > 0 && len(body) > r.MaxBodySize {
+		return partial
+	}
+	return partial + "...[truncated]"
+}
+
+// loggableType проверяет, логируется ли тело с типом содержимого contentType
+func (r Redaction) loggableType(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return mediaType == "" || r.loggable(mediaType, nil)
+}
+
+func (r Redaction) loggable(mediaType string, body []byte) bool {
+	if mediaType == "" {
+		return utf8.Valid(body)
+	}
+	for _, contentType := range r.ContentTypes {
+		if strings.HasPrefix(mediaType, contentType) {
+			return true
+		}
+	}
+	return false
+}
+
+// maskJson скрывает поля JSON тела, false - тело не удалось разобрать
+func (r Redaction) maskJson(body []byte) ([]byte, bool) {
+	if len(r.BodyFields) == 0 {
+		return body, true
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return body, false
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return body, false
+	}
+
+	paths := make([][]string, 0, len(r.BodyFields))
+	for _, field := range r.BodyFields {
+		paths = append(paths, strings.Split(field, "."))
+	}
+	value = r.maskValue(value, nil, paths)
+
+	masked, err := json.Marshal(value)
+	if err != nil {
+		return body, false
+	}
+	return masked, true
+}
+
+// maskValue заменяет значения полей, путь которых соответствует одному из paths
+func (r Redaction) maskValue(value interface{}, path []string, paths [][]string) interface{} {
+	if len(path) > 0 && matchesAny(path, paths) {
+		return r.mask()
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			v[key] = r.maskValue(item, append(path[:len(path):len(path)], key), paths)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = r.maskValue(item, append(path[:len(path):len(path)], fmt.Sprint(i)), paths)
+		}
+	}
+	return value
+}
+
+// maskForm скрывает поля формы, false - тело не удалось разобрать
+func (r Redaction) maskForm(body []byte) ([]byte, bool) {
+	if len(r.BodyFields) == 0 {
+		return body, true
+	}
+
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return body, false
+	}
+
+	for key := range values {
+		if containsFold(r.BodyFields, key) {
+			values[key] = []string{r.mask()}
+		}
+	}
+	return []byte(values.Encode()), true
+}
+
+func matchesAny(path []string, paths [][]string) bool {
+	for _, pattern := range paths {
+		if len(pattern) == 1 {
+			if strings.EqualFold(pattern[0], path[len(path)-1]) {
+				return true
+			}
+			continue
+		}
+		if len(pattern) != len(path) {
+			continue
+		}
+		matched := true
+		for i := range pattern {
+			if pattern[i] != "*" && !strings.EqualFold(pattern[i], path[i]) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
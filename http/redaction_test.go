@@ -0,0 +1,90 @@
+package http
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedaction_Header(t *testing.T) {
+	r := DefaultRedaction()
+
+	assert.Equal(t, DefaultMask, r.Header("authorization", []string{"Bearer secret"}))
+	assert.Equal(t, DefaultMask, r.Header("Cookie", []string{"session=1", "lang=ru"}))
+	assert.Equal(t, "application/json, text/plain", r.Header("Accept", []string{"application/json", "text/plain"}))
+}
+
+func TestRedaction_Query(t *testing.T) {
+	r := DefaultRedaction()
+
+	assert.Equal(t, []string{DefaultMask, DefaultMask}, r.Query("Token", []string{"a", "b"}))
+	assert.Equal(t, []string{"42"}, r.Query("page", []string{"42"}))
+}
+
+func TestRedaction_JsonBody(t *testing.T) {
+	r := DefaultRedaction()
+	r.BodyFields = append(r.BodyFields, "payments.*.account", "user.name")
+
+	body := r.Body("application/json; charset=utf-8", []byte(`{
+		"user": {"name": "Ivan", "password": "qwerty", "profile": {"name": "public"}},
+		"cards": [{"cardNumber": "4111111111111111", "holder": "IVAN"}],
+		"payments": [{"account": "40817810", "amount": 10.50}],
+		"token": {"value": "abc"}
+	}`))
+
+	var masked map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(body), &masked))
+	assert.Equal(t, map[string]interface{}{
+		"user":     map[string]interface{}{"name": DefaultMask, "password": DefaultMask, "profile": map[string]interface{}{"name": "public"}},
+		"cards":    []interface{}{map[string]interface{}{"cardNumber": DefaultMask, "holder": "IVAN"}},
+		"payments": []interface{}{map[string]interface{}{"account": DefaultMask, "amount": 10.50}},
+		"token":    DefaultMask,
+	}, masked)
+}
+
+func TestRedaction_MalformedJsonBody(t *testing.T) {
+	r := DefaultRedaction()
+
+	assert.Equal(t, "[body omitted: application/json, 21 bytes]", r.Body("application/json", []byte(`{"broken": "password"`)))
+	assert.Equal(t, "[body omitted: application/json, 23 bytes]", r.Body("application/json", []byte(`{"password":"hunter2",}`)))
+	assert.Equal(t, "[body omitted: application/json, 33 bytes]", r.Body("application/json", []byte(`{"a":1} {"password":"hunter2"}   `)))
+}
+
+func TestRedaction_FormBody(t *testing.T) {
+	r := DefaultRedaction()
+
+	assert.Equal(t, "login=ivan&password=%2A%2A%2A", r.Body("application/x-www-form-urlencoded", []byte("login=ivan&password=qwerty")))
+	assert.Equal(t, "[body omitted: application/x-www-form-urlencoded, 22 bytes]",
+		r.Body("application/x-www-form-urlencoded", []byte("password=hunter2&q=%zz")))
+}
+
+func TestRedaction_Defaults(t *testing.T) {
+	r := Redaction{}.withDefaults()
+
+	assert.Equal(t, DefaultMask, r.Header("Authorization", []string{"Bearer abc"}))
+	assert.Equal(t, []string{DefaultMask}, r.Query("token", []string{"abc"}))
+	assert.Equal(t, `{"password":"***"}`, r.Body("application/json", []byte(`{"password":"hunter2"}`)))
+
+	r = Redaction{Headers: []string{}}.withDefaults()
+	assert.Equal(t, "Bearer abc", r.Header("Authorization", []string{"Bearer abc"}))
+}
+
+func TestRedaction_ContentType(t *testing.T) {
+	r := DefaultRedaction()
+
+	assert.Equal(t, "[body omitted: image/png, 4 bytes]", r.Body("image/png", []byte{0x89, 'P', 'N', 'G'}))
+	assert.Equal(t, "[body omitted: multipart/form-data; boundary=x, 3 bytes]", r.Body("multipart/form-data; boundary=x", []byte("--x")))
+	assert.Equal(t, "[body omitted: , 2 bytes]", r.Body("", []byte{0xff, 0xfe}))
+	assert.Equal(t, "plain", r.Body("", []byte("plain")))
+	assert.Equal(t, "", r.Body("application/json", nil))
+}
+
+func TestRedaction_MaxBodySize(t *testing.T) {
+	r := DefaultRedaction()
+	r.MaxBodySize = 5
+
+	assert.Equal(t, "hello...[truncated, 11 bytes]", r.Body("text/plain", []byte("hello world")))
+	assert.Equal(t, strings.Repeat("a", 5), r.Body("text/plain", []byte(strings.Repeat("a", 5))))
+}
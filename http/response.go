@@ -1,12 +1,17 @@
 package http
 
 import (
+	appErrors "github.com/AeroAgency/golang-helpers-lib/errors"
 	"github.com/gin-gonic/gin"
 	"net/http"
 )
 
 // Response Ответы сервиса
 type Response struct {
+	// Renderer формат ответа об ошибке, по умолчанию {applicationErrorCode, message, debug}
+	Renderer appErrors.Renderer
+	// Production не передавать текст ошибки в ответе
+	Production bool
 }
 
 // OK 200
@@ -48,9 +53,35 @@ func (r Response) ForbiddenError(c *gin.Context, debug string) {
 }
 
 func (r Response) err(c *gin.Context, httpErrorCode int, applicationErrorCode, message, debug string) {
-	c.JSON(httpErrorCode, gin.H{
-		"applicationErrorCode": applicationErrorCode,
-		"message":              message,
-		"debug":                debug,
-	})
+	problem := appErrors.Problem{
+		Status:  httpErrorCode,
+		Title:   http.StatusText(httpErrorCode),
+		Code:    applicationErrorCode,
+		Message: message,
+		Debug:   debug,
+	}.WithRequest(c)
+	if r.Production {
+		problem.Debug = ""
+	}
+
+	renderer := r.Renderer
+	if renderer == nil {
+		renderer = responseRenderer{}
+	}
+	renderer.Render(c, problem)
+}
+
+// responseRenderer ответ об ошибке в формате {applicationErrorCode, message, debug, requestId}
+type responseRenderer struct{}
+
+func (r responseRenderer) Render(c *gin.Context, problem appErrors.Problem) {
+	body := gin.H{
+		"applicationErrorCode": problem.Code,
+		"message":              problem.Message,
+		"debug":                problem.Debug,
+	}
+	if problem.RequestId != "" {
+		body["requestId"] = problem.RequestId
+	}
+	c.JSON(problem.Status, body)
 }
@@ -0,0 +1,110 @@
+package logger
+
+import (
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+// Поля записи журнала доступа
+const (
+	FieldReturnCode     = "return-code"
+	FieldTraceId        = "X-Trace-Id"
+	FieldRequestId      = "X-Request-Id"
+	FieldMethod         = "http-method"
+	FieldPath           = "Path"
+	FieldUserAgent      = "User-Agent"
+	FieldRequestHeaders = "request-headers"
+	FieldLatency        = "Latency"
+	FieldRequestParams  = "request-params"
+	FieldRequestBody    = "request-body"
+	FieldResponseBody   = "response-body"
+)
+
+// AccessLogOptions настройки журнала доступа
+type AccessLogOptions struct {
+	// SkipUserAgents префиксы User-Agent, запросы с которыми не логируются
+	SkipUserAgents []string
+	// SkipPaths пути запросов, которые не логируются. Путь, оканчивающийся на "*", задает префикс
+	SkipPaths []string
+	// SuccessSampling логировать только каждый n-й успешный (статус меньше 400) запрос, 0 и 1 - каждый
+	SuccessSampling int
+	// Levels уровень лога по классу статуса ответа (2 - 2xx, 4 - 4xx, ...), по умолчанию Info.
+	// zerolog.Disabled отключает логирование класса
+	Levels map[int]zerolog.Level
+	// Fields поля записи, пустой список - все поля
+	Fields []string
+}
+
+// DefaultAccessLogOptions не логирует запросы kube-probe и Prometheus
+func DefaultAccessLogOptions() AccessLogOptions {
+	return AccessLogOptions{
+		SkipUserAgents: []string{"kube-probe", "Prometheus"},
+	}
+}
+
+// AccessLog применяет настройки журнала доступа, безопасен для конкурентного использования
+type AccessLog struct {
+	options   AccessLogOptions
+	fields    map[string]bool
+	successes atomic.Uint64
+}
+
+// NewAccessLog Конструктор
+func NewAccessLog(options AccessLogOptions) *AccessLog {
+	a := &AccessLog{options: options}
+	if len(options.Fields) > 0 {
+		a.fields = make(map[string]bool, len(options.Fields))
+		for _, field := range options.Fields {
+			a.fields[field] = true
+		}
+	}
+
+	return a
+}
+
+// Skip проверяет, исключен ли запрос из журнала по User-Agent или пути
+func (a *AccessLog) Skip(c *gin.Context) bool {
+	agent := c.Request.Header.Get(userAgent)
+	for _, prefix := range a.options.SkipUserAgents {
+		if strings.HasPrefix(agent, prefix) {
+			return true
+		}
+	}
+
+	path := c.Request.URL.Path
+	for _, skip := range a.options.SkipPaths {
+		if strings.HasSuffix(skip, "*") && strings.HasPrefix(path, strings.TrimSuffix(skip, "*")) || path == skip {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Event возвращает запись лога для ответа со статусом status
+// или nil, если запись не нужна по уровню или выборке
+func (a *AccessLog) Event(logger zerolog.Logger, status int) *zerolog.Event {
+	level, ok := a.options.Levels[status/100]
+	if !ok {
+		level = zerolog.InfoLevel
+	}
+	if level == zerolog.Disabled {
+		return nil
+	}
+
+	if status < 400 && a.options.SuccessSampling > 1 {
+		if (a.successes.Add(1)-1)%uint64(a.options.SuccessSampling) != 0 {
+			return nil
+		}
+	}
+
+	return logger.WithLevel(level)
+}
+
+// Field проверяет, включено ли поле в запись
+func (a *AccessLog) Field(name string) bool {
+	return a.fields == nil || a.fields[name]
+}
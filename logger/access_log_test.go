@@ -0,0 +1,77 @@
+package logger
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func request(path, agent string) *gin.Context {
+	c := &gin.Context{Request: httptest.NewRequest(http.MethodGet, path, nil)}
+	c.Request.Header.Set(userAgent, agent)
+	return c
+}
+
+func TestAccessLog_Skip(t *testing.T) {
+	options := DefaultAccessLogOptions()
+	options.SkipPaths = []string{"/health", "/metrics*"}
+	accessLog := NewAccessLog(options)
+
+	assert.True(t, accessLog.Skip(request("/orders", "kube-probe/1.27")))
+	assert.True(t, accessLog.Skip(request("/health", "curl")))
+	assert.True(t, accessLog.Skip(request("/metrics/go", "curl")))
+	assert.False(t, accessLog.Skip(request("/healthz", "curl")))
+	assert.False(t, accessLog.Skip(request("/orders", "curl")))
+}
+
+func TestAccessLog_Event(t *testing.T) {
+	accessLog := NewAccessLog(AccessLogOptions{Levels: map[int]zerolog.Level{
+		2: zerolog.Disabled,
+		4: zerolog.WarnLevel,
+		5: zerolog.ErrorLevel,
+	}})
+	logger := zerolog.New(nil)
+
+	assert.Nil(t, accessLog.Event(logger, http.StatusOK))
+	assert.NotNil(t, accessLog.Event(logger, http.StatusFound))
+	assert.NotNil(t, accessLog.Event(logger, http.StatusNotFound))
+	assert.Nil(t, accessLog.Event(logger.Level(zerolog.ErrorLevel), http.StatusNotFound))
+	assert.NotNil(t, accessLog.Event(logger.Level(zerolog.ErrorLevel), http.StatusBadGateway))
+}
+
+func TestAccessLog_SuccessSampling(t *testing.T) {
+	accessLog := NewAccessLog(AccessLogOptions{SuccessSampling: 10})
+	logger := zerolog.New(nil)
+
+	var mu sync.Mutex
+	var logged int
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if accessLog.Event(logger, http.StatusOK) != nil {
+				mu.Lock()
+				logged++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 10, logged)
+	assert.NotNil(t, accessLog.Event(logger, http.StatusInternalServerError))
+}
+
+func TestAccessLog_Field(t *testing.T) {
+	assert.True(t, NewAccessLog(AccessLogOptions{}).Field(FieldLatency))
+
+	accessLog := NewAccessLog(AccessLogOptions{Fields: []string{FieldPath, FieldReturnCode}})
+	assert.True(t, accessLog.Field(FieldPath))
+	assert.False(t, accessLog.Field(FieldLatency))
+}
@@ -1,10 +1,10 @@
 package logger
 
 import (
+	"github.com/AeroAgency/golang-helpers-lib/requestid"
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
-	"strings"
 	"time"
 )
 
@@ -15,45 +15,61 @@ const (
 	accept      = "Accept"
 )
 
-// exceptions - User-Agent exceptions
-var (
-	exception  bool
-	exceptions = [...]string{
-		"kube-probe", "Prometheus",
-	}
-)
-
-// Logger -
+// Logger журнал доступа с настройками по умолчанию
 func Logger() gin.HandlerFunc {
+	return LoggerWithOptions(DefaultAccessLogOptions())
+}
+
+// LoggerWithOptions журнал доступа с настройками options
+func LoggerWithOptions(options AccessLogOptions) gin.HandlerFunc {
+	accessLog := NewAccessLog(options)
+
 	return func(c *gin.Context) {
 		t1 := time.Now()
 		defer func() {
-			for i := range exceptions {
-				if strings.HasPrefix(c.Request.Header.Get(userAgent), exceptions[i]) {
-					exception = true
-				}
+			if accessLog.Skip(c) {
+				return
+			}
+			event := accessLog.Event(log.Logger, c.Writer.Status())
+			if event == nil {
+				return
+			}
+
+			message := zerolog.Dict()
+			if accessLog.Field(FieldMethod) {
+				message.Str(FieldMethod, c.Request.Method)
+			}
+			if accessLog.Field(FieldRequestHeaders) {
+				message.Dict(FieldRequestHeaders, zerolog.Dict().
+					Str(contentType, c.Request.Header.Get(contentType)).
+					Str(userAgent, c.Request.Header.Get(userAgent)).
+					Str(accept, c.Request.Header.Get(accept)),
+				)
+			}
+			if accessLog.Field(FieldPath) {
+				message.Str(FieldPath, c.Request.URL.Path)
 			}
-			if !exception {
+			if accessLog.Field(FieldLatency) {
+				message.Dur(FieldLatency, time.Since(t1))
+			}
+			if accessLog.Field(FieldRequestParams) {
 				requestParams := zerolog.Dict()
 				for k, v := range c.Request.URL.Query() {
 					requestParams.Strs(k, v)
 				}
-				log.Info().
-					Int("return-code", c.Writer.Status()).
-					Str("X-Trace-Id", c.Writer.Header().Get("X-Trace-Id")).
-					Dict("message", zerolog.Dict().
-						Str("http-method", c.Request.Method).
-						Dict("request-headers", zerolog.Dict().
-							Str(contentType, c.Request.Header.Get(contentType)).
-							Str(userAgent, c.Request.Header.Get(userAgent)).
-							Str(accept, c.Request.Header.Get(accept)),
-						).
-						Str("Path", c.Request.URL.Path).
-						Dur("Latency", time.Since(t1)).
-						Dict("request-params", requestParams),
-					).Msg("")
-			}
-			exception = false
+				message.Dict(FieldRequestParams, requestParams)
+			}
+
+			if accessLog.Field(FieldReturnCode) {
+				event.Int(FieldReturnCode, c.Writer.Status())
+			}
+			if accessLog.Field(FieldTraceId) {
+				event.Str(FieldTraceId, c.Writer.Header().Get("X-Trace-Id"))
+			}
+			if ids := requestid.FromGin(c); ids.RequestId != "" && accessLog.Field(FieldRequestId) {
+				event.Str(FieldRequestId, ids.RequestId)
+			}
+			event.Dict("message", message).Msg("")
 		}()
 		c.Next()
 	}
@@ -9,3 +9,9 @@ type AppLoggerInterface interface {
 	// Error Сообщение об ошибке
 	Error(err error, msg string, keysAndValues ...interface{})
 }
+
+// WarnLoggerInterface Логгер с уровнем предупреждений. Реализуется дополнительно к AppLoggerInterface
+type WarnLoggerInterface interface {
+	// Warn Предупреждение
+	Warn(msg string, keysAndValues ...interface{})
+}
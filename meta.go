@@ -3,7 +3,9 @@ package helpers
 import (
 	"context"
 	b64 "encoding/base64"
+	"github.com/AeroAgency/golang-helpers-lib/requestid"
 	"google.golang.org/grpc/metadata"
+	"strings"
 )
 
 type Meta struct{}
@@ -27,3 +29,29 @@ func (m Meta) GetDecodedParam(ctx context.Context, key string) (string, error) {
 	}
 	return string(decodedValue), nil
 }
+
+// GetRequestIds возвращает идентификаторы запроса из метаданных gRPC (x-request-id, x-correlation-id),
+// недостающие берутся из контекста
+func (m Meta) GetRequestIds(ctx context.Context) requestid.Ids {
+	ids := requestid.FromContext(ctx)
+	if requestId := m.GetParam(ctx, strings.ToLower(requestid.HeaderRequestId)); requestId != "" {
+		ids.RequestId = requestId
+	}
+	if correlationId := m.GetParam(ctx, strings.ToLower(requestid.HeaderCorrelationId)); correlationId != "" {
+		ids.CorrelationId = correlationId
+	}
+	return ids
+}
+
+// ContextWithRequestIds возвращает контекст с идентификаторами запроса из метаданных gRPC,
+// недостающие генерируются
+func (m Meta) ContextWithRequestIds(ctx context.Context) context.Context {
+	ids := m.GetRequestIds(ctx)
+	if ids.RequestId == "" {
+		ids.RequestId = requestid.New()
+	}
+	if ids.CorrelationId == "" {
+		ids.CorrelationId = ids.RequestId
+	}
+	return requestid.NewContext(ctx, ids)
+}
@@ -15,6 +15,11 @@ type Metrics interface {
 	Observe(metricName string, value float64, labelValues ...string) error
 }
 
+// GaugeMetrics метрики, поддерживающие установку значения
+type GaugeMetrics interface {
+	Set(metricName string, value float64, labelValues ...string) error
+}
+
 type PullMetrics struct {
 	metrics map[string]prometheus.Collector
 }
@@ -63,6 +68,20 @@ func (m *PullMetrics) Observe(metricName string, value float64, labelValues ...s
 	return nil
 }
 
+func (m *PullMetrics) Set(metricName string, value float64, labelValues ...string) error {
+	metric, ok := m.metrics[metricName]
+
+	if !ok {
+		return errors.Errorf("metric '%s' not existed.", metricName)
+	}
+
+	if err := set(metric, value, labelValues); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 func inc(metric prometheus.Collector, labelValues []string) error {
 	switch metric := metric.(type) {
 	case *prometheus.CounterVec:
@@ -95,3 +114,15 @@ func observe(metric prometheus.Collector, value float64, labelValues []string) e
 	}
 	return nil
 }
+
+func set(metric prometheus.Collector, value float64, labelValues []string) error {
+	switch metric := metric.(type) {
+	case *prometheus.GaugeVec:
+		metric.WithLabelValues(labelValues...).Set(value)
+	case prometheus.Gauge:
+		metric.Set(value)
+	default:
+		return errors.Errorf("metric is not Gauge type")
+	}
+	return nil
+}
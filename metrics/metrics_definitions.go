@@ -59,3 +59,70 @@ var (
 		}, []string{"type"}),
 	}
 )
+
+var (
+	EventDispatched = Metric{
+		Name: "event_dispatched_total",
+		Collector: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: "event",
+			Name:      "dispatched_total",
+			Help:      "Total number of dispatched events",
+		}, []string{"event"}),
+	}
+	EventListenerError = Metric{
+		Name: "event_listener_error_total",
+		Collector: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: "event",
+			Name:      "listener_error_total",
+			Help:      "Total number of event listener execution errors",
+		}, []string{"event"}),
+	}
+	EventListenerExecutionTime = Metric{
+		Name: "event_listener_execution_time_seconds",
+		Collector: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Subsystem: "event",
+			Name:      "listener_execution_time_seconds",
+			Help:      "Time of event listener execution",
+			Buckets:   []float64{0.1, 0.3, 0.5, 0.6, 1, 2, 5, 10, 20, 60},
+		}, []string{"event"}),
+	}
+	EventQueueDepth = Metric{
+		Name: "event_queue_depth",
+		Collector: prometheus.NewGauge(prometheus.GaugeOpts{
+			Subsystem: "event",
+			Name:      "queue_depth",
+			Help:      "Number of events waiting for delivery",
+		}),
+	}
+)
+
+var (
+	AppError = Metric{
+		Name: "app_error_total",
+		Collector: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: "app",
+			Name:      "error_total",
+			Help:      "Total number of handled application errors",
+		}, []string{"code"}),
+	}
+)
+
+var (
+	HttpClientRequest = Metric{
+		Name: "http_client_request_total",
+		Collector: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Subsystem: "http_client",
+			Name:      "request_total",
+			Help:      "Total number of outgoing http requests",
+		}, []string{"host", "method", "code"}),
+	}
+	HttpClientRequestExecutionTime = Metric{
+		Name: "http_client_request_execution_time_seconds",
+		Collector: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Subsystem: "http_client",
+			Name:      "request_execution_time_seconds",
+			Help:      "Time of outgoing http request execution",
+			Buckets:   []float64{0.1, 0.3, 0.5, 0.6, 1, 2, 5, 10, 20, 60},
+		}, []string{"host", "method"}),
+	}
+)
@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/AeroAgency/golang-helpers-lib/event"
+)
+
+// EventObserver собирает метрики диспетчера событий.
+// Для метрики глубины очереди Metrics должен реализовывать GaugeMetrics
+type EventObserver struct {
+	metrics Metrics
+}
+
+func NewEventObserver(metrics Metrics) *EventObserver {
+	return &EventObserver{metrics: metrics}
+}
+
+func (o EventObserver) Dispatched(_ context.Context, name event.Name) {
+	_ = o.metrics.Inc(EventDispatched.Name, string(name))
+}
+
+func (o EventObserver) QueueDepth(depth int) {
+	if gauges, ok := o.metrics.(GaugeMetrics); ok {
+		_ = gauges.Set(EventQueueDepth.Name, float64(depth))
+	}
+}
+
+func (o EventObserver) ListenerStarted(ctx context.Context, _ event.Name) context.Context {
+	return ctx
+}
+
+func (o EventObserver) ListenerFinished(_ context.Context, name event.Name, duration time.Duration, err error) {
+	_ = o.metrics.Observe(EventListenerExecutionTime.Name, duration.Seconds(), string(name))
+	if err != nil {
+		_ = o.metrics.Inc(EventListenerError.Name, string(name))
+	}
+}
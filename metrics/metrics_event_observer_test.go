@@ -0,0 +1,81 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/AeroAgency/golang-helpers-lib/event"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+type countingMetrics struct {
+	incs     map[string]int
+	observes map[string]int
+}
+
+func (m *countingMetrics) Inc(metricName string, labelValues ...string) error {
+	m.incs[metricName]++
+	return nil
+}
+
+func (m *countingMetrics) Observe(metricName string, value float64, labelValues ...string) error {
+	m.observes[metricName]++
+	return nil
+}
+
+func newEventMetrics() (*PullMetrics, []prometheus.Collector) {
+	collectors := []prometheus.Collector{
+		prometheus.NewCounterVec(prometheus.CounterOpts{Name: "dispatched_total"}, []string{"event"}),
+		prometheus.NewCounterVec(prometheus.CounterOpts{Name: "listener_error_total"}, []string{"event"}),
+		prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "listener_execution_time_seconds"}, []string{"event"}),
+		prometheus.NewGauge(prometheus.GaugeOpts{Name: "queue_depth"}),
+	}
+	m := NewPullMetrics(prometheus.NewRegistry(), []Metric{
+		{Name: EventDispatched.Name, Collector: collectors[0]},
+		{Name: EventListenerError.Name, Collector: collectors[1]},
+		{Name: EventListenerExecutionTime.Name, Collector: collectors[2]},
+		{Name: EventQueueDepth.Name, Collector: collectors[3]},
+	})
+
+	return m, collectors
+}
+
+func TestEventObserver(t *testing.T) {
+	m, collectors := newEventMetrics()
+	d := event.NewDispatcher().AddObserver(NewEventObserver(m))
+	assert.NoError(t, d.RegisterContext(event.ListenerFunc(func(ctx context.Context, e interface{}) error {
+		if e == "fail" {
+			return errors.New("listener failed")
+		}
+		return nil
+	}), "order.created"))
+
+	assert.NoError(t, d.DispatchSync(context.Background(), "order.created", "ok"))
+	assert.Error(t, d.DispatchSync(context.Background(), "order.created", "fail"))
+	assert.NoError(t, d.Dispatch("order.created", "ok"))
+	assert.NoError(t, d.Close(context.Background()))
+
+	dispatched := collectors[0].(*prometheus.CounterVec)
+	listenerErrors := collectors[1].(*prometheus.CounterVec)
+	assert.Equal(t, float64(3), testutil.ToFloat64(dispatched.WithLabelValues("order.created")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(listenerErrors.WithLabelValues("order.created")))
+	assert.Equal(t, 1, testutil.CollectAndCount(collectors[2]))
+	assert.Equal(t, float64(0), testutil.ToFloat64(collectors[3]))
+}
+
+func TestEventObserver_QueueDepth(t *testing.T) {
+	m, collectors := newEventMetrics()
+	NewEventObserver(m).QueueDepth(7)
+	assert.Equal(t, float64(7), testutil.ToFloat64(collectors[3]))
+
+	// Metrics без Set: глубина очереди не записывается
+	counting := &countingMetrics{incs: map[string]int{}, observes: map[string]int{}}
+	observer := NewEventObserver(counting)
+	observer.QueueDepth(7)
+	observer.Dispatched(context.Background(), "order.created")
+	assert.Equal(t, map[string]int{EventDispatched.Name: 1}, counting.incs)
+	assert.Empty(t, counting.observes)
+}
@@ -0,0 +1,30 @@
+package metrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPullMetrics_Set(t *testing.T) {
+	gauge := prometheus.NewGauge(prometheus.GaugeOpts{Name: "queue_depth"})
+	gaugeVec := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "pool_size"}, []string{"pool"})
+	counter := prometheus.NewCounter(prometheus.CounterOpts{Name: "requests_total"})
+	m := NewPullMetrics(prometheus.NewRegistry(), []Metric{
+		{Name: "queue_depth", Collector: gauge},
+		{Name: "pool_size", Collector: gaugeVec},
+		{Name: "requests_total", Collector: counter},
+	})
+
+	assert.NoError(t, m.Set("queue_depth", 5))
+	assert.NoError(t, m.Set("queue_depth", 3))
+	assert.Equal(t, float64(3), testutil.ToFloat64(gauge))
+
+	assert.NoError(t, m.Set("pool_size", 10, "db"))
+	assert.Equal(t, float64(10), testutil.ToFloat64(gaugeVec.WithLabelValues("db")))
+
+	assert.EqualError(t, m.Set("requests_total", 1), "metric is not Gauge type")
+	assert.EqualError(t, m.Set("unknown", 1), "metric 'unknown' not existed.")
+}
@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"errors"
+
+	"github.com/AeroAgency/golang-helpers-lib/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const gormSpanKey = "tracing:span"
+
+// RegisterGormTracingCallbacks создает спаны OpenTelemetry для запросов GORM.
+// Родительский спан берется из контекста запроса (db.WithContext).
+// При пустом tracer используется глобальный TracerProvider
+func RegisterGormTracingCallbacks(db *gorm.DB, tracer trace.Tracer) {
+	if tracer == nil {
+		tracer = otel.Tracer(tracing.InstrumentationName)
+	}
+
+	_ = db.Callback().Create().Before("*").Register("tracing:start_create", gormStartSpan(tracer, "create"))
+	_ = db.Callback().Create().After("*").Register("tracing:end_create", gormEndSpan)
+	_ = db.Callback().Query().Before("*").Register("tracing:start_query", gormStartSpan(tracer, "query"))
+	_ = db.Callback().Query().After("*").Register("tracing:end_query", gormEndSpan)
+	_ = db.Callback().Update().Before("*").Register("tracing:start_update", gormStartSpan(tracer, "update"))
+	_ = db.Callback().Update().After("*").Register("tracing:end_update", gormEndSpan)
+	_ = db.Callback().Delete().Before("*").Register("tracing:start_delete", gormStartSpan(tracer, "delete"))
+	_ = db.Callback().Delete().After("*").Register("tracing:end_delete", gormEndSpan)
+	_ = db.Callback().Row().Before("*").Register("tracing:start_row", gormStartSpan(tracer, "row"))
+	_ = db.Callback().Row().After("*").Register("tracing:end_row", gormEndSpan)
+	_ = db.Callback().Raw().Before("*").Register("tracing:start_raw", gormStartSpan(tracer, "raw"))
+	_ = db.Callback().Raw().After("*").Register("tracing:end_raw", gormEndSpan)
+}
+
+func gormStartSpan(tracer trace.Tracer, operation string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx, span := tracer.Start(db.Statement.Context, "gorm "+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attribute.String("db.system", "postgresql")),
+		)
+		db.Statement.Context = ctx
+		db.InstanceSet(gormSpanKey, span)
+	}
+}
+
+func gormEndSpan(db *gorm.DB) {
+	value, ok := db.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span := value.(trace.Span)
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.statement", db.Statement.SQL.String()),
+		attribute.String("db.sql.table", db.Statement.Table),
+		attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+	)
+	if err := db.Statement.Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
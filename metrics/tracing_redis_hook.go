@@ -0,0 +1,69 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/AeroAgency/golang-helpers-lib/tracing"
+	goRedis "github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RedisTracingHook создает спаны OpenTelemetry для команд Redis
+type RedisTracingHook struct {
+	tracer trace.Tracer
+}
+
+// NewRedisTracingHook Конструктор. При пустом tracer используется глобальный TracerProvider
+func NewRedisTracingHook(tracer trace.Tracer) *RedisTracingHook {
+	if tracer == nil {
+		tracer = otel.Tracer(tracing.InstrumentationName)
+	}
+
+	return &RedisTracingHook{tracer: tracer}
+}
+
+func (h RedisTracingHook) BeforeProcess(ctx context.Context, cmd goRedis.Cmder) (context.Context, error) {
+	ctx, _ = h.tracer.Start(ctx, "redis "+cmd.FullName(),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("db.system", "redis"), attribute.String("db.operation", cmd.FullName())),
+	)
+
+	return ctx, nil
+}
+
+func (h RedisTracingHook) AfterProcess(ctx context.Context, cmd goRedis.Cmder) error {
+	span := trace.SpanFromContext(ctx)
+	redisSpanError(span, cmd)
+	span.End()
+
+	return nil
+}
+
+func (h RedisTracingHook) BeforeProcessPipeline(ctx context.Context, cmds []goRedis.Cmder) (context.Context, error) {
+	ctx, _ = h.tracer.Start(ctx, "redis pipeline",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("db.system", "redis"), attribute.Int("db.redis.num_cmd", len(cmds))),
+	)
+
+	return ctx, nil
+}
+
+func (h RedisTracingHook) AfterProcessPipeline(ctx context.Context, cmds []goRedis.Cmder) error {
+	span := trace.SpanFromContext(ctx)
+	for _, cmd := range cmds {
+		redisSpanError(span, cmd)
+	}
+	span.End()
+
+	return nil
+}
+
+func redisSpanError(span trace.Span, cmd goRedis.Cmder) {
+	if err := cmd.Err(); err != nil && err != goRedis.Nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
@@ -0,0 +1,52 @@
+package metrics
+
+import (
+	"context"
+
+	"github.com/AeroAgency/golang-helpers-lib/tracing"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// S3TracingMiddleware создает спаны OpenTelemetry для запросов к S3.
+// При пустом tracer используется глобальный TracerProvider
+func S3TracingMiddleware(tracer trace.Tracer) middleware.DeserializeMiddleware {
+	if tracer == nil {
+		tracer = otel.Tracer(tracing.InstrumentationName)
+	}
+
+	return middleware.DeserializeMiddlewareFunc("TraceRequest", func(
+		ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler,
+	) (
+		out middleware.DeserializeOutput, metadata middleware.Metadata, err error,
+	) {
+		attributes := []attribute.KeyValue{attribute.String("rpc.system", "aws-api"), attribute.String("rpc.service", "s3")}
+		name := "s3"
+		if request, ok := in.Request.(*smithyhttp.Request); ok {
+			name += " " + request.Method
+			attributes = append(attributes,
+				attribute.String("http.method", request.Method),
+				attribute.String("http.target", request.URL.Path),
+			)
+		}
+
+		ctx, span := tracer.Start(ctx, name, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attributes...))
+		defer span.End()
+		if request, ok := in.Request.(*smithyhttp.Request); ok {
+			tracing.Inject(ctx, propagation.HeaderCarrier(request.Header))
+		}
+
+		out, metadata, err = next.HandleDeserialize(ctx, in)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+
+		return out, metadata, err
+	})
+}
@@ -0,0 +1,137 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	goRedis "github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/migrator"
+	"gorm.io/gorm/schema"
+)
+
+func newTestTracer() (trace.Tracer, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	return tp.Tracer("test"), exporter
+}
+
+// dryRunDialector диалект GORM без подключения к базе данных, запросы только формируются
+type dryRunDialector struct{}
+
+func (dryRunDialector) Name() string { return "dryrun" }
+func (dryRunDialector) Initialize(db *gorm.DB) error {
+	callbacks.RegisterDefaultCallbacks(db, &callbacks.Config{})
+	return nil
+}
+func (d dryRunDialector) Migrator(db *gorm.DB) gorm.Migrator {
+	return migrator.Migrator{Config: migrator.Config{DB: db, Dialector: d}}
+}
+func (dryRunDialector) DataTypeOf(*schema.Field) string                { return "" }
+func (dryRunDialector) DefaultValueOf(*schema.Field) clause.Expression { return clause.Expr{} }
+func (dryRunDialector) BindVarTo(writer clause.Writer, _ *gorm.Statement, _ interface{}) {
+	_ = writer.WriteByte('?')
+}
+func (dryRunDialector) QuoteTo(writer clause.Writer, s string)      { _, _ = writer.WriteString(s) }
+func (dryRunDialector) Explain(sql string, _ ...interface{}) string { return sql }
+
+type order struct {
+	ID int
+}
+
+func TestRegisterGormTracingCallbacks(t *testing.T) {
+	tracer, exporter := newTestTracer()
+	db, err := gorm.Open(dryRunDialector{}, &gorm.Config{DryRun: true, Logger: logger.Discard})
+	assert.NoError(t, err)
+	RegisterGormTracingCallbacks(db, tracer)
+	assert.NoError(t, db.Callback().Delete().Register("test:fail", func(db *gorm.DB) {
+		_ = db.AddError(errors.New("constraint violation"))
+	}))
+
+	ctx, parent := tracer.Start(context.Background(), "handler")
+	var orders []order
+	assert.NoError(t, db.WithContext(ctx).Where("id = ?", 42).Find(&orders).Error)
+	assert.Error(t, db.WithContext(ctx).Delete(&order{ID: 1}).Error)
+	parent.End()
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 3)
+	query, deleted := spans[0], spans[1]
+	assert.Equal(t, "gorm query", query.Name)
+	assert.Equal(t, trace.SpanKindClient, query.SpanKind)
+	assert.Equal(t, parent.SpanContext().SpanID(), query.Parent.SpanID())
+	assert.Contains(t, query.Attributes, attribute.String("db.statement", "SELECT * FROM orders WHERE id = ?"))
+	assert.Contains(t, query.Attributes, attribute.String("db.sql.table", "orders"))
+	assert.Equal(t, codes.Unset, query.Status.Code)
+
+	assert.Equal(t, "gorm delete", deleted.Name)
+	assert.Equal(t, codes.Error, deleted.Status.Code)
+	assert.Equal(t, "constraint violation", deleted.Status.Description)
+}
+
+func TestRedisTracingHook(t *testing.T) {
+	tracer, exporter := newTestTracer()
+	hook := NewRedisTracingHook(tracer)
+	ctx := context.Background()
+
+	get := goRedis.NewStringCmd(ctx, "get", "order:1")
+	cmdCtx, err := hook.BeforeProcess(ctx, get)
+	assert.NoError(t, err)
+	get.SetErr(goRedis.Nil)
+	assert.NoError(t, hook.AfterProcess(cmdCtx, get))
+
+	set := goRedis.NewStatusCmd(ctx, "set", "order:1", "paid")
+	set.SetErr(errors.New("READONLY"))
+	cmds := []goRedis.Cmder{goRedis.NewStringCmd(ctx, "get", "order:2"), set}
+	pipelineCtx, err := hook.BeforeProcessPipeline(ctx, cmds)
+	assert.NoError(t, err)
+	assert.NoError(t, hook.AfterProcessPipeline(pipelineCtx, cmds))
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 2)
+	assert.Equal(t, "redis get", spans[0].Name)
+	assert.Contains(t, spans[0].Attributes, attribute.String("db.system", "redis"))
+	assert.Equal(t, codes.Unset, spans[0].Status.Code)
+
+	assert.Equal(t, "redis pipeline", spans[1].Name)
+	assert.Contains(t, spans[1].Attributes, attribute.Int("db.redis.num_cmd", 2))
+	assert.Equal(t, codes.Error, spans[1].Status.Code)
+	assert.Equal(t, "READONLY", spans[1].Status.Description)
+}
+
+func TestS3TracingMiddleware(t *testing.T) {
+	tracer, exporter := newTestTracer()
+	httpRequest, _ := http.NewRequest(http.MethodPut, "https://bucket.s3.amazonaws.com/orders/1.json", nil)
+	request := &smithyhttp.Request{Request: httpRequest}
+
+	var traceparent string
+	next := middleware.DeserializeHandlerFunc(func(ctx context.Context, in middleware.DeserializeInput) (
+		middleware.DeserializeOutput, middleware.Metadata, error,
+	) {
+		traceparent = in.Request.(*smithyhttp.Request).Header.Get("traceparent")
+		return middleware.DeserializeOutput{}, middleware.Metadata{}, errors.New("access denied")
+	})
+
+	_, _, err := S3TracingMiddleware(tracer).HandleDeserialize(context.Background(), middleware.DeserializeInput{Request: request}, next)
+	assert.EqualError(t, err, "access denied")
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 1)
+	assert.Equal(t, "s3 PUT", spans[0].Name)
+	assert.Contains(t, spans[0].Attributes, attribute.String("http.target", "/orders/1.json"))
+	assert.Contains(t, traceparent, spans[0].SpanContext.TraceID().String())
+	assert.Equal(t, codes.Error, spans[0].Status.Code)
+}
@@ -0,0 +1,166 @@
+package requestid
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Заголовки идентификаторов запроса
+const (
+	HeaderRequestId     = "X-Request-Id"
+	HeaderCorrelationId = "X-Correlation-Id"
+)
+
+// MaxLength максимальная длина идентификатора, принимаемого из заголовков
+const MaxLength = 128
+
+// GinKey ключ идентификаторов в gin.Context
+const GinKey = "requestIds"
+
+// Ids идентификаторы запроса. RequestId уникален для каждого запроса,
+// CorrelationId общий для цепочки запросов между сервисами
+type Ids struct {
+	RequestId     string
+	CorrelationId string
+}
+
+type contextKey struct{}
+
+// New генерирует идентификатор запроса
+func New() string {
+	return uuid.NewV4().String()
+}
+
+// NewContext возвращает контекст, содержащий идентификаторы
+func NewContext(ctx context.Context, ids Ids) context.Context {
+	return context.WithValue(ctx, contextKey{}, ids)
+}
+
+// FromContext возвращает идентификаторы из контекста
+func FromContext(ctx context.Context) Ids {
+	if ctx == nil {
+		return Ids{}
+	}
+	ids, _ := ctx.Value(contextKey{}).(Ids)
+	return ids
+}
+
+// FromGin возвращает идентификаторы из gin.Context или из контекста запроса
+func FromGin(c *gin.Context) Ids {
+	if c == nil {
+		return Ids{}
+	}
+	if value, ok := c.Get(GinKey); ok {
+		if ids, ok := value.(Ids); ok {
+			return ids
+		}
+	}
+	if c.Request != nil {
+		return FromContext(c.Request.Context())
+	}
+	return Ids{}
+}
+
+// Valid проверяет идентификатор из заголовка: непустой, не длиннее MaxLength,
+// содержит только символы [A-Za-z0-9._:-]
+func Valid(id string) bool {
+	if id == "" || len(id) > MaxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '.', c == '_', c == ':', c == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// FromHeader возвращает идентификаторы из заголовков, недостающие и не прошедшие проверку Valid
+// генерируются: RequestId - новый, CorrelationId - равный RequestId
+func FromHeader(header http.Header) Ids {
+	ids := Ids{
+		RequestId:     header.Get(HeaderRequestId),
+		CorrelationId: header.Get(HeaderCorrelationId),
+	}
+	if !Valid(ids.RequestId) {
+		ids.RequestId = New()
+	}
+	if !Valid(ids.CorrelationId) {
+		ids.CorrelationId = ids.RequestId
+	}
+	return ids
+}
+
+// Inject записывает идентификаторы из контекста в заголовки
+func Inject(ctx context.Context, header http.Header) {
+	ids := FromContext(ctx)
+	if ids.RequestId != "" {
+		header.Set(HeaderRequestId, ids.RequestId)
+	}
+	if ids.CorrelationId != "" {
+		header.Set(HeaderCorrelationId, ids.CorrelationId)
+	}
+}
+
+// Logger возвращает логер, добавляющий в записи идентификаторы из контекста
+func Logger(ctx context.Context, logger zerolog.Logger) zerolog.Logger {
+	ids := FromContext(ctx)
+	if ids.RequestId == "" && ids.CorrelationId == "" {
+		return logger
+	}
+
+	return logger.With().
+		Str("request_id", ids.RequestId).
+		Str("correlation_id", ids.CorrelationId).
+		Logger()
+}
+
+// Middleware читает идентификаторы из заголовков X-Request-Id и X-Correlation-Id или генерирует их,
+// сохраняет в gin.Context и контексте запроса, возвращает в заголовках ответа.
+// В контекст запроса добавляется логер с идентификаторами, доступный через zerolog.Ctx
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ids := FromHeader(c.Request.Header)
+		ctx := NewContext(c.Request.Context(), ids)
+		logger := Logger(ctx, log.Logger)
+		ctx = logger.WithContext(ctx)
+		c.Request = c.Request.WithContext(ctx)
+		c.Set(GinKey, ids)
+		c.Header(HeaderRequestId, ids.RequestId)
+		c.Header(HeaderCorrelationId, ids.CorrelationId)
+
+		c.Next()
+	}
+}
+
+// Transport передает идентификаторы из контекста запроса в заголовках исходящих запросов
+type Transport struct {
+	// Base транспорт, выполняющий запросы, по умолчанию http.DefaultTransport
+	Base http.RoundTripper
+}
+
+func (t Transport) RoundTrip(request *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	ids := FromContext(request.Context())
+	if ids.RequestId == "" && ids.CorrelationId == "" {
+		return base.RoundTrip(request)
+	}
+
+	// RoundTripper не должен изменять исходный запрос
+	request = request.Clone(request.Context())
+	Inject(request.Context(), request.Header)
+	return base.RoundTrip(request)
+}
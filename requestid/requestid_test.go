@@ -0,0 +1,118 @@
+package requestid
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var buf bytes.Buffer
+	logger := log.Logger
+	log.Logger = zerolog.New(&buf)
+	defer func() { log.Logger = logger }()
+
+	var fromGin, fromContext Ids
+	router := gin.New()
+	router.Use(Middleware())
+	router.GET("/orders", func(c *gin.Context) {
+		fromGin = FromGin(c)
+		fromContext = FromContext(c.Request.Context())
+		zerolog.Ctx(c.Request.Context()).Info().Msg("handled")
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	request.Header.Set(HeaderCorrelationId, "chain-1")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, request)
+
+	assert.NotEmpty(t, fromGin.RequestId)
+	assert.Equal(t, "chain-1", fromGin.CorrelationId)
+	assert.Equal(t, fromGin, fromContext)
+	assert.Equal(t, fromGin.RequestId, w.Header().Get(HeaderRequestId))
+	assert.Equal(t, "chain-1", w.Header().Get(HeaderCorrelationId))
+
+	var entry map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, fromGin.RequestId, entry["request_id"])
+	assert.Equal(t, "chain-1", entry["correlation_id"])
+}
+
+func TestFromHeader(t *testing.T) {
+	header := http.Header{}
+	header.Set(HeaderRequestId, "req-1")
+	assert.Equal(t, Ids{RequestId: "req-1", CorrelationId: "req-1"}, FromHeader(header))
+
+	generated := FromHeader(http.Header{})
+	assert.Len(t, generated.RequestId, 36)
+	assert.Equal(t, generated.RequestId, generated.CorrelationId)
+	assert.NotEqual(t, generated.RequestId, FromHeader(http.Header{}).RequestId)
+}
+
+func TestFromHeader_Invalid(t *testing.T) {
+	for _, id := range []string{strings.Repeat("a", MaxLength+1), "req 1", "req-1\r\nX-Admin: 1", "<script>", "заказ"} {
+		header := http.Header{}
+		header.Set(HeaderRequestId, id)
+		header.Set(HeaderCorrelationId, id)
+		ids := FromHeader(header)
+		assert.Len(t, ids.RequestId, 36, id)
+		assert.Equal(t, ids.RequestId, ids.CorrelationId, id)
+	}
+
+	header := http.Header{}
+	header.Set(HeaderRequestId, "bad id")
+	header.Set(HeaderCorrelationId, "chain-1")
+	ids := FromHeader(header)
+	assert.Len(t, ids.RequestId, 36)
+	assert.Equal(t, "chain-1", ids.CorrelationId)
+
+	valid := "svc.orders:req_1-" + strings.Repeat("0", MaxLength-18)
+	assert.True(t, Valid(valid))
+	header.Set(HeaderRequestId, valid)
+	assert.Equal(t, valid, FromHeader(header).RequestId)
+}
+
+func TestFromGin(t *testing.T) {
+	assert.Equal(t, Ids{}, FromGin(nil))
+	assert.Equal(t, Ids{}, FromGin(&gin.Context{}))
+
+	ids := Ids{RequestId: "req-1", CorrelationId: "chain-1"}
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	c := &gin.Context{Request: request.WithContext(NewContext(request.Context(), ids))}
+	assert.Equal(t, ids, FromGin(c))
+}
+
+func TestTransport(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: Transport{}}
+
+	ctx := NewContext(context.Background(), Ids{RequestId: "req-1", CorrelationId: "chain-1"})
+	request, _ := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	response, err := client.Do(request)
+	assert.NoError(t, err)
+	response.Body.Close()
+
+	assert.Equal(t, "req-1", received.Get(HeaderRequestId))
+	assert.Equal(t, "chain-1", received.Get(HeaderCorrelationId))
+	assert.Empty(t, request.Header.Get(HeaderRequestId), "the original request must not be modified")
+
+	request, _ = http.NewRequest(http.MethodGet, server.URL, nil)
+	response, err = client.Do(request)
+	assert.NoError(t, err)
+	response.Body.Close()
+	assert.Empty(t, received.Get(HeaderRequestId))
+}
@@ -0,0 +1,52 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// GinKey ключ трассировщика в gin.Context
+const GinKey = "tracer"
+
+type contextKey struct{}
+
+// ContextWithTracer возвращает контекст, содержащий трассировщик
+func ContextWithTracer(ctx context.Context, tracer TracerInterface) context.Context {
+	return context.WithValue(ctx, contextKey{}, tracer)
+}
+
+// FromContext возвращает трассировщик из контекста. Если его нет, используется
+// спан OpenTelemetry из контекста, иначе Noop
+func FromContext(ctx context.Context) TracerInterface {
+	if ctx == nil {
+		return Noop{}
+	}
+	if tracer, ok := ctx.Value(contextKey{}).(TracerInterface); ok && tracer != nil {
+		return tracer
+	}
+	if span := trace.SpanFromContext(ctx); span.SpanContext().IsValid() {
+		return NewOtelTracer(span)
+	}
+
+	return Noop{}
+}
+
+// FromGin возвращает трассировщик, установленный по ключу GinKey, или трассировщик
+// из контекста запроса. Если трассировка не настроена, возвращается Noop
+func FromGin(c *gin.Context) TracerInterface {
+	if c == nil {
+		return Noop{}
+	}
+	if value, ok := c.Get(GinKey); ok {
+		if tracer, ok := value.(TracerInterface); ok && tracer != nil {
+			return tracer
+		}
+	}
+	if c.Request != nil {
+		return FromContext(c.Request.Context())
+	}
+
+	return Noop{}
+}
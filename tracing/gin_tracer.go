@@ -0,0 +1,15 @@
+package tracing
+
+import (
+	"context"
+
+	tracerAdapter "github.com/AeroAgency/go-gin-tracer"
+)
+
+// GinTracerProvider создает спаны трассировщиком go-gin-tracer (Jaeger/OpenTracing)
+type GinTracerProvider struct{}
+
+func (p GinTracerProvider) Start(ctx context.Context, name string) (context.Context, TracerInterface) {
+	tracer := tracerAdapter.NewTracer(name)
+	return ContextWithTracer(ctx, tracer), tracer
+}
@@ -0,0 +1,52 @@
+package tracing
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// GinMiddleware создает спан OpenTelemetry для запроса, продолжая трассировку из заголовка traceparent.
+// Трассировщик доступен через FromGin и FromContext, идентификатор трассировки передается
+// в заголовке ответа X-Trace-Id. В контекст запроса добавляется логер с идентификаторами трассировки
+// и спана, доступный через zerolog.Ctx. При пустом tracer используется глобальный TracerProvider
+func GinMiddleware(tracer trace.Tracer) gin.HandlerFunc {
+	if tracer == nil {
+		tracer = otel.Tracer(InstrumentationName)
+	}
+
+	return func(c *gin.Context) {
+		ctx := Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+		ctx, span := tracer.Start(ctx, fmt.Sprintf("handler %s:%s", c.Request.Method, c.FullPath()),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.method", c.Request.Method),
+				attribute.String("http.route", c.FullPath()),
+				attribute.String("http.target", c.Request.URL.Path),
+			),
+		)
+		defer span.End()
+
+		otelTracer := NewOtelTracer(span)
+		logger := Logger(ctx, ContextLogger(ctx, log.Logger))
+		ctx = logger.WithContext(ctx)
+		c.Request = c.Request.WithContext(ContextWithTracer(ctx, otelTracer))
+		c.Set(GinKey, otelTracer)
+		c.Header(TraceIdHeader, span.SpanContext().TraceID().String())
+
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(attribute.Int("http.status_code", status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
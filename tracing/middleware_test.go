@@ -0,0 +1,122 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestGinMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	router := gin.New()
+	router.Use(GinMiddleware(tp.Tracer(InstrumentationName)))
+	router.GET("/orders/:id", func(c *gin.Context) {
+		FromGin(c).SetTag("order", c.Param("id"))
+		_, child := tp.Tracer(InstrumentationName).Start(c.Request.Context(), "repository")
+		child.End()
+		c.Status(http.StatusBadGateway)
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/orders/42", nil)
+	request.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, request)
+
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", w.Header().Get(TraceIdHeader))
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 2)
+	child, server := spans[0], spans[1]
+	assert.Equal(t, "handler GET:/orders/:id", server.Name)
+	assert.Equal(t, trace.SpanKindServer, server.SpanKind)
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent.SpanID().String())
+	assert.True(t, server.Parent.IsRemote())
+	assert.Equal(t, server.SpanContext.SpanID(), child.Parent.SpanID())
+	assert.Contains(t, server.Attributes, attribute.String("order", "42"))
+	assert.Contains(t, server.Attributes, attribute.Int("http.status_code", http.StatusBadGateway))
+	assert.Equal(t, codes.Error, server.Status.Code)
+}
+
+func TestGinMiddleware_ContextLogger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	buf := &bytes.Buffer{}
+	logger := log.Logger
+	log.Logger = zerolog.New(buf)
+	defer func() { log.Logger = logger }()
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		// логер, добавленный предыдущим middleware, дополняется идентификаторами трассировки
+		requestLogger := log.Logger.With().Str("request_id", "req-1").Logger()
+		c.Request = c.Request.WithContext(requestLogger.WithContext(c.Request.Context()))
+	}, GinMiddleware(sdktrace.NewTracerProvider().Tracer(InstrumentationName)))
+	router.GET("/orders", func(c *gin.Context) {
+		zerolog.Ctx(c.Request.Context()).Info().Msg("handled")
+	})
+
+	request := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	request.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), request)
+
+	var entry map[string]interface{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", entry["trace_id"])
+	assert.NotEmpty(t, entry["span_id"])
+	assert.Equal(t, "req-1", entry["request_id"])
+	assert.Equal(t, "handled", entry["message"])
+}
+
+func TestInjectExtract(t *testing.T) {
+	tp := sdktrace.NewTracerProvider()
+	ctx, span := tp.Tracer(InstrumentationName).Start(context.Background(), "client")
+	defer span.End()
+
+	header := http.Header{}
+	Inject(ctx, propagation.HeaderCarrier(header))
+	assert.NotEmpty(t, header.Get("traceparent"))
+
+	extracted := Extract(context.Background(), propagation.HeaderCarrier(header))
+	assert.Equal(t, TraceId(ctx), TraceId(extracted))
+	assert.Equal(t, "", TraceId(context.Background()))
+}
+
+func TestLogger(t *testing.T) {
+	tp := sdktrace.NewTracerProvider()
+	ctx, span := tp.Tracer(InstrumentationName).Start(context.Background(), "handler")
+	defer span.End()
+
+	var buf bytes.Buffer
+	logger := Logger(ctx, zerolog.New(&buf))
+	logger.Info().Msg("order created")
+	logger = Logger(context.Background(), zerolog.New(&buf))
+	logger.Info().Msg("no trace")
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 2)
+
+	var entry map[string]interface{}
+	assert.NoError(t, json.Unmarshal(lines[0], &entry))
+	assert.Equal(t, span.SpanContext().TraceID().String(), entry["trace_id"])
+	assert.Equal(t, span.SpanContext().SpanID().String(), entry["span_id"])
+
+	entry = nil
+	assert.NoError(t, json.Unmarshal(lines[1], &entry))
+	assert.NotContains(t, entry, "trace_id")
+}
@@ -0,0 +1,95 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentationName имя библиотеки для трассировщиков OpenTelemetry
+const InstrumentationName = "github.com/AeroAgency/golang-helpers-lib"
+
+// OtelTracer трассировщик на основе спана OpenTelemetry
+type OtelTracer struct {
+	span trace.Span
+}
+
+func NewOtelTracer(span trace.Span) *OtelTracer {
+	return &OtelTracer{span: span}
+}
+
+// Span возвращает спан OpenTelemetry
+func (t *OtelTracer) Span() trace.Span {
+	return t.span
+}
+
+func (t *OtelTracer) SetTag(key string, value interface{}) {
+	t.span.SetAttributes(attributeOf(key, value))
+}
+
+func (t *OtelTracer) Log(key, message string) {
+	t.span.AddEvent(key, trace.WithAttributes(attribute.String("message", message)))
+}
+
+func (t *OtelTracer) LogData(key string, data interface{}) {
+	t.span.AddEvent(key, trace.WithAttributes(attributeOf("data", data)))
+}
+
+func (t *OtelTracer) LogError(err interface{}) {
+	e, ok := err.(error)
+	if !ok {
+		e = fmt.Errorf("%v", err)
+	}
+	t.span.RecordError(e)
+	t.span.SetStatus(codes.Error, e.Error())
+}
+
+func (t *OtelTracer) Close() {
+	t.span.End()
+}
+
+// OtelProvider создает спаны трассировщиком OpenTelemetry.
+// При пустом Tracer используется глобальный TracerProvider
+type OtelProvider struct {
+	Tracer trace.Tracer
+}
+
+func (p OtelProvider) Start(ctx context.Context, name string) (context.Context, TracerInterface) {
+	tracer := p.Tracer
+	if tracer == nil {
+		tracer = otel.Tracer(InstrumentationName)
+	}
+	ctx, span := tracer.Start(ctx, name)
+	otelTracer := NewOtelTracer(span)
+
+	return ContextWithTracer(ctx, otelTracer), otelTracer
+}
+
+func attributeOf(key string, value interface{}) attribute.KeyValue {
+	switch v := value.(type) {
+	case string:
+		return attribute.String(key, v)
+	case bool:
+		return attribute.Bool(key, v)
+	case int:
+		return attribute.Int(key, v)
+	case int64:
+		return attribute.Int64(key, v)
+	case float64:
+		return attribute.Float64(key, v)
+	case fmt.Stringer:
+		return attribute.String(key, v.String())
+	case error:
+		return attribute.String(key, v.Error())
+	}
+	if data, err := json.Marshal(value); err == nil {
+		return attribute.String(key, string(data))
+	}
+
+	return attribute.String(key, fmt.Sprintf("%v", value))
+}
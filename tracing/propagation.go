@@ -0,0 +1,37 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceIdHeader заголовок ответа с идентификатором трассировки
+const TraceIdHeader = "X-Trace-Id"
+
+// Propagator передает контекст трассировки между сервисами в заголовках
+// W3C traceparent, tracestate и baggage
+var Propagator propagation.TextMapPropagator = propagation.NewCompositeTextMapPropagator(
+	propagation.TraceContext{},
+	propagation.Baggage{},
+)
+
+// Inject записывает контекст трассировки в заголовки
+func Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	Propagator.Inject(ctx, carrier)
+}
+
+// Extract возвращает контекст, содержащий контекст трассировки из заголовков
+func Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	return Propagator.Extract(ctx, carrier)
+}
+
+// TraceId возвращает идентификатор трассировки OpenTelemetry из контекста, пустую строку, если его нет
+func TraceId(ctx context.Context) string {
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+		return spanContext.TraceID().String()
+	}
+
+	return ""
+}
@@ -0,0 +1,46 @@
+package tracing
+
+import (
+	"context"
+)
+
+// TracerInterface Интерфейс трассировки операции (спана).
+// Ему удовлетворяет трассировщик go-gin-tracer
+type TracerInterface interface {
+	// SetTag Устанавливает тег спана
+	SetTag(key string, value interface{})
+	// Log Добавляет в спан сообщение
+	Log(key, message string)
+	// LogData Добавляет в спан данные
+	LogData(key string, data interface{})
+	// LogError Добавляет в спан ошибку и отмечает спан как ошибочный
+	LogError(err interface{})
+	// Close Завершает спан
+	Close()
+}
+
+// ProviderInterface Интерфейс создания спанов
+type ProviderInterface interface {
+	// Start Создает спан и возвращает контекст, содержащий его
+	Start(ctx context.Context, name string) (context.Context, TracerInterface)
+}
+
+// Noop трассировщик, не выполняющий действий. Используется, если трассировка не настроена
+type Noop struct{}
+
+func (n Noop) SetTag(key string, value interface{}) {}
+
+func (n Noop) Log(key, message string) {}
+
+func (n Noop) LogData(key string, data interface{}) {}
+
+func (n Noop) LogError(err interface{}) {}
+
+func (n Noop) Close() {}
+
+// NoopProvider создает спаны Noop
+type NoopProvider struct{}
+
+func (p NoopProvider) Start(ctx context.Context, name string) (context.Context, TracerInterface) {
+	return ContextWithTracer(ctx, Noop{}), Noop{}
+}
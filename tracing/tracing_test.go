@@ -0,0 +1,86 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newProvider() (OtelProvider, *tracetest.InMemoryExporter) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	return OtelProvider{Tracer: tp.Tracer(InstrumentationName)}, exporter
+}
+
+func TestFromGin_Noop(t *testing.T) {
+	assert.Equal(t, Noop{}, FromGin(nil))
+	assert.Equal(t, Noop{}, FromGin(&gin.Context{}))
+
+	c := &gin.Context{Request: httptest.NewRequest(http.MethodGet, "/", nil)}
+	assert.Equal(t, Noop{}, FromGin(c))
+
+	c.Set(GinKey, "not a tracer")
+	assert.Equal(t, Noop{}, FromGin(c))
+}
+
+func TestFromGin(t *testing.T) {
+	provider, _ := newProvider()
+	ctx, tracer := provider.Start(context.Background(), "handler")
+
+	c := &gin.Context{Request: httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)}
+	assert.Same(t, tracer, FromGin(c))
+
+	other := &OtelTracer{}
+	c.Set(GinKey, other)
+	assert.Same(t, other, FromGin(c))
+}
+
+func TestFromContext(t *testing.T) {
+	assert.Equal(t, Noop{}, FromContext(context.Background()))
+
+	provider, exporter := newProvider()
+	ctx, span := provider.Tracer.Start(context.Background(), "consumer")
+	FromContext(ctx).SetTag("queue", "orders")
+	span.End()
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 1)
+	assert.Contains(t, spans[0].Attributes, attribute.String("queue", "orders"))
+}
+
+func TestOtelTracer(t *testing.T) {
+	provider, exporter := newProvider()
+	_, tracer := provider.Start(context.Background(), "handler GET:/orders/:id")
+
+	tracer.SetTag("order", 42)
+	tracer.SetTag("payload", map[string]int{"amount": 10})
+	tracer.Log("Header [Accept]", "application/json")
+	tracer.LogData("[Request body]", "{}")
+	tracer.LogError(errors.New("db timeout"))
+	tracer.Close()
+
+	spans := exporter.GetSpans()
+	assert.Len(t, spans, 1)
+	span := spans[0]
+	assert.Equal(t, "handler GET:/orders/:id", span.Name)
+	assert.Contains(t, span.Attributes, attribute.Int("order", 42))
+	assert.Contains(t, span.Attributes, attribute.String("payload", `{"amount":10}`))
+	assert.Equal(t, codes.Error, span.Status.Code)
+	assert.Equal(t, "db timeout", span.Status.Description)
+
+	var events []string
+	for _, event := range span.Events {
+		events = append(events, event.Name)
+	}
+	assert.Equal(t, []string{"Header [Accept]", "[Request body]", "exception"}, events)
+}
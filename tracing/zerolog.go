@@ -0,0 +1,29 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ContextLogger возвращает логер из контекста (zerolog.Ctx) или logger, если в контексте его нет
+func ContextLogger(ctx context.Context, logger zerolog.Logger) zerolog.Logger {
+	if ctxLogger := zerolog.Ctx(ctx); ctxLogger.GetLevel() != zerolog.Disabled {
+		return *ctxLogger
+	}
+	return logger
+}
+
+// Logger возвращает логер, добавляющий в записи идентификаторы трассировки и спана из контекста
+func Logger(ctx context.Context, logger zerolog.Logger) zerolog.Logger {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return logger
+	}
+
+	return logger.With().
+		Str("trace_id", spanContext.TraceID().String()).
+		Str("span_id", spanContext.SpanID().String()).
+		Logger()
+}
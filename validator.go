@@ -4,7 +4,10 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	appErrors "github.com/AeroAgency/golang-helpers-lib/errors"
 	"github.com/thedevsaddam/govalidator"
+	"net/url"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -51,7 +54,7 @@ func (validator Validator) ValidateProto(inputStruct interface{}, rules map[stri
 	e := v.ValidateStruct()
 	if len(e) > 0 {
 		errorsData, _ := json.MarshalIndent(e, "", "  ")
-		return errors.New(string(errorsData))
+		return validationError(opts, e, string(errorsData))
 	}
 	return nil
 }
@@ -67,7 +70,7 @@ func (validator Validator) ValidateProtoWithCustomMessages(inputStruct interface
 	e := v.ValidateStruct()
 	if len(e) > 0 {
 		for k := range e {
-			return errors.New(e.Get(k))
+			return validationError(opts, e, e.Get(k))
 		}
 	}
 	return nil
@@ -89,7 +92,49 @@ func ValidateStruct(data interface{}, rules map[string][]string, messages map[st
 			firstErrorMsg = item[0]
 			break
 		}
-		return fmt.Errorf(firstErrorMsg)
+		return validationError(opts, validationResult, firstErrorMsg)
 	}
 	return nil
 }
+
+// validationError Ошибка BadRequest с нарушениями правил по каждому полю
+func validationError(opts govalidator.Options, result url.Values, msg string) error {
+	fields := make([]string, 0, len(result))
+	for field := range result {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	var violations []appErrors.Violation
+	for _, field := range fields {
+		violations = append(violations, fieldViolations(opts, field, result[field])...)
+	}
+
+	return appErrors.NewValidationError(msg, violations...)
+}
+
+// fieldViolations Нарушения правил поля. Правило определяется повторной проверкой поля по каждому правилу отдельно.
+// В результат проверки попадают только поля, все правила которых проверялись (поле непустое или обязательное),
+// поэтому повторная проверка выполняется с RequiredDefault. Если число нарушений не совпало с числом
+// сообщений, нарушения возвращаются без правила
+func fieldViolations(opts govalidator.Options, field string, messages []string) []appErrors.Violation {
+	var violations []appErrors.Violation
+	for _, rule := range opts.Rules[field] {
+		ruleOpts := opts
+		ruleOpts.Rules = map[string][]string{field: {rule}}
+		ruleOpts.RequiredDefault = true
+		ruleName, _, _ := strings.Cut(rule, ":")
+		for _, message := range govalidator.New(ruleOpts).ValidateStruct()[field] {
+			violations = append(violations, appErrors.Violation{Field: field, Rule: ruleName, Message: message})
+		}
+	}
+
+	if len(violations) != len(messages) {
+		violations = violations[:0]
+		for _, message := range messages {
+			violations = append(violations, appErrors.Violation{Field: field, Message: message})
+		}
+	}
+
+	return violations
+}
@@ -0,0 +1,72 @@
+package helpers
+
+import (
+	"testing"
+
+	appErrors "github.com/AeroAgency/golang-helpers-lib/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/thedevsaddam/govalidator"
+)
+
+type validatorInput struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+func TestValidator_ValidateProto(t *testing.T) {
+	err := Validator{}.ValidateProto(&validatorInput{Name: "a1", Age: 5}, map[string][]string{
+		"name": {"alpha", "min_string_len:3"},
+		"age":  {"numeric_between:18,99"},
+	})
+
+	assert.Equal(t, appErrors.BadRequest, appErrors.GetType(err))
+	assert.Equal(t, []appErrors.Violation{
+		{Field: "age", Rule: "numeric_between", Message: "The age field must be numeric value between 18 and 99"},
+		{Field: "name", Rule: "alpha", Message: "The name may only contain letters"},
+		{Field: "name", Rule: "min_string_len", Message: "The name field must be minium 3 char"},
+	}, appErrors.GetViolations(err))
+
+	assert.NoError(t, Validator{}.ValidateProto(&validatorInput{Name: "Иван", Age: 20}, map[string][]string{
+		"name": {"min_string_len:3"},
+		"age":  {"numeric_between:18,99"},
+	}))
+}
+
+func TestValidator_ValidateProtoWithCustomMessages(t *testing.T) {
+	err := Validator{}.ValidateProtoWithCustomMessages(&validatorInput{Name: "a1"}, map[string][]string{
+		"name": {"alpha", "max_string_len:1"},
+	}, map[string][]string{
+		"name": {"alpha:Имя может содержать только буквы"},
+	})
+
+	assert.Equal(t, "Имя может содержать только буквы", err.Error())
+	assert.Equal(t, []appErrors.Violation{
+		{Field: "name", Rule: "alpha", Message: "Имя может содержать только буквы"},
+		{Field: "name", Rule: "max_string_len", Message: "The name field must be maximum 1 char"},
+	}, appErrors.GetViolations(err))
+}
+
+func TestValidateStruct(t *testing.T) {
+	err := ValidateStruct(&validatorInput{Age: 20}, map[string][]string{
+		"name": {"required", "alpha"},
+		"age":  {"numeric_between:18,99"},
+	}, nil)
+
+	assert.Equal(t, "The name field is required", err.Error())
+	assert.Equal(t, []appErrors.Violation{
+		{Field: "name", Rule: "required", Message: "The name field is required"},
+		{Field: "name", Rule: "alpha", Message: "The name may only contain letters"},
+	}, appErrors.GetViolations(err))
+}
+
+func TestFieldViolations_Fallback(t *testing.T) {
+	opts := govalidator.Options{
+		Data:  &validatorInput{Name: "a1"},
+		Rules: map[string][]string{"name": {"alpha", "min_string_len:3"}},
+	}
+
+	// Число сообщений отличается от числа нарушений при повторной проверке по каждому правилу
+	assert.Equal(t, []appErrors.Violation{
+		{Field: "name", Message: "name is invalid"},
+	}, fieldViolations(opts, "name", []string{"name is invalid"}))
+}